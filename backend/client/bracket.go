@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/google/uuid"
+)
+
+// PlaceOCOOrder places a one-cancels-the-other order: a limit order at
+// price paired with a stop-limit order (stopPrice trigger, stopLimitPrice
+// limit) on the opposite side of the position. Binance cancels whichever
+// leg doesn't execute once the other does.
+func (tc *TradingClient) PlaceOCOOrder(symbol, side, quantity, price, stopPrice, stopLimitPrice string) (*OrderResult, error) {
+	if tc.apiKey == "" || tc.apiKey == "your_testnet_api_key_here" {
+		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
+	}
+
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	sideType, err := binanceSideType(side)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizedQty, err := tc.normalizeQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	normalizedPrice := tc.FormatPrice(symbol, parseFloatOrZero(price))
+	normalizedStopPrice := tc.FormatPrice(symbol, parseFloatOrZero(stopPrice))
+	normalizedStopLimitPrice := tc.FormatPrice(symbol, parseFloatOrZero(stopLimitPrice))
+
+	if err := tc.syncTime(); err != nil {
+		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
+	}
+
+	oco, err := tc.client.NewCreateOCOService().
+		Symbol(symbol).
+		Side(sideType).
+		Quantity(normalizedQty).
+		Price(normalizedPrice).
+		StopPrice(normalizedStopPrice).
+		StopLimitPrice(normalizedStopLimitPrice).
+		StopLimitTimeInForce(binance.TimeInForceTypeGTC).
+		Do(tc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to place OCO order: %w", err)
+	}
+
+	var orderID int64
+	if len(oco.OrderReports) > 0 {
+		orderID = oco.OrderReports[0].OrderID
+	}
+
+	result := &OrderResult{
+		OrderID:  orderID,
+		Symbol:   symbol,
+		Side:     side,
+		Type:     "OCO",
+		Price:    normalizedPrice,
+		Quantity: normalizedQty,
+		Status:   string(oco.ListOrderStatus),
+	}
+
+	log.Printf("✅ OCO Order placed: %s %s qty=%s tp=%s sl=%s/%s (OrderListID: %d)",
+		side, symbol, normalizedQty, normalizedPrice, normalizedStopPrice, normalizedStopLimitPrice, oco.OrderListID)
+	return result, nil
+}
+
+// PlaceStopLossMarket places a stop-loss order that becomes a market order
+// once stopPrice is reached.
+func (tc *TradingClient) PlaceStopLossMarket(symbol, side, quantity, stopPrice string) (*OrderResult, error) {
+	return tc.placeStopTriggeredOrder(symbol, side, quantity, stopPrice, binance.OrderTypeStopLoss)
+}
+
+// PlaceTakeProfitMarket places a take-profit order that becomes a market
+// order once stopPrice is reached.
+func (tc *TradingClient) PlaceTakeProfitMarket(symbol, side, quantity, stopPrice string) (*OrderResult, error) {
+	return tc.placeStopTriggeredOrder(symbol, side, quantity, stopPrice, binance.OrderTypeTakeProfit)
+}
+
+func (tc *TradingClient) placeStopTriggeredOrder(symbol, side, quantity, stopPrice string, orderType binance.OrderType) (*OrderResult, error) {
+	if tc.apiKey == "" || tc.apiKey == "your_testnet_api_key_here" {
+		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
+	}
+
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	sideType, err := binanceSideType(side)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizedQty, err := tc.normalizeQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	normalizedStopPrice := tc.FormatPrice(symbol, parseFloatOrZero(stopPrice))
+
+	if err := tc.syncTime(); err != nil {
+		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
+	}
+
+	order, err := tc.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(sideType).
+		Type(orderType).
+		Quantity(normalizedQty).
+		StopPrice(normalizedStopPrice).
+		NewClientOrderID(uuid.New().String()).
+		Do(tc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to place %s order: %w", orderType, err)
+	}
+
+	result := &OrderResult{
+		OrderID:             order.OrderID,
+		Symbol:              order.Symbol,
+		Side:                string(order.Side),
+		Type:                string(order.Type),
+		Price:               order.Price,
+		Quantity:            order.OrigQuantity,
+		Status:              string(order.Status),
+		ExecutedQty:         order.ExecutedQuantity,
+		CummulativeQuoteQty: order.CummulativeQuoteQuantity,
+	}
+
+	log.Printf("✅ %s Order placed: %s %s stop=%s (OrderID: %d)", orderType, quantity, symbol, normalizedStopPrice, order.OrderID)
+	return result, nil
+}
+
+// OrderSpec describes one leg of a PlaceBracket call.
+type OrderSpec struct {
+	Symbol    string
+	Side      string // "BUY" or "SELL"
+	Quantity  string
+	Price     string // limit price; leave empty on entry for a market order
+	StopPrice string // trigger price; only read on the sl leg
+}
+
+// BracketResult carries the outcomes of a PlaceBracket call: the filled
+// entry and, once placed, the protective OCO covering take-profit/stop-loss.
+type BracketResult struct {
+	Entry *OrderResult
+	OCO   *OrderResult
+}
+
+// PlaceBracket submits entry (market if entry.Price is empty, limit
+// otherwise) and then, once uds reports entry's order as FILLED, places tp
+// and sl together as a single OCO order so triggering one cancels the
+// other. It blocks until the OCO is placed or ctx is cancelled — callers
+// that don't want to block the calling goroutine on a fill that may never
+// come should run it in its own goroutine.
+func (tc *TradingClient) PlaceBracket(ctx context.Context, uds *UserDataStream, entry, tp, sl OrderSpec) (*BracketResult, error) {
+	entryResult, err := tc.placeBracketEntry(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place bracket entry: %w", err)
+	}
+
+	// Market orders (entry.Price == "") commonly fill synchronously, and the
+	// executionReport can already have fired (or race with) the REST
+	// response above — if we registered the listener unconditionally we'd
+	// wait on a fill that already happened and block until ctx is
+	// cancelled, leaving the position unprotected with no OCO. Skip
+	// straight to the OCO when the entry is already reported FILLED.
+	if entryResult.Status != "FILLED" {
+		filled := make(chan struct{}, 1)
+		unsubscribe := uds.OnOrderUpdate(func(update OrderUpdate) {
+			if update.OrderID == entryResult.OrderID && update.Status == "FILLED" {
+				select {
+				case filled <- struct{}{}:
+				default:
+				}
+			}
+		})
+		defer unsubscribe()
+
+		select {
+		case <-filled:
+		case <-ctx.Done():
+			return &BracketResult{Entry: entryResult}, ctx.Err()
+		}
+	}
+
+	ocoResult, err := tc.PlaceOCOOrder(tp.Symbol, tp.Side, tp.Quantity, tp.Price, sl.StopPrice, sl.Price)
+	if err != nil {
+		return &BracketResult{Entry: entryResult}, fmt.Errorf("entry filled but failed to place protective OCO: %w", err)
+	}
+
+	log.Printf("🎯 Bracket complete: entry %d filled, protective OCO placed", entryResult.OrderID)
+	return &BracketResult{Entry: entryResult, OCO: ocoResult}, nil
+}
+
+// placeBracketEntry places spec as a market order if Price is empty, or a
+// limit order otherwise, reusing the existing Place*Order methods.
+func (tc *TradingClient) placeBracketEntry(spec OrderSpec) (*OrderResult, error) {
+	if spec.Price == "" {
+		return tc.PlaceMarketOrder(spec.Symbol, spec.Side, spec.Quantity)
+	}
+
+	switch spec.Side {
+	case "BUY":
+		return tc.PlaceLimitBuyOrder(spec.Symbol, spec.Quantity, spec.Price)
+	case "SELL":
+		return tc.PlaceLimitSellOrder(spec.Symbol, spec.Quantity, spec.Price)
+	default:
+		return nil, fmt.Errorf("invalid order side: %s", spec.Side)
+	}
+}
+
+// parseFloatOrZero parses s as a float64, returning 0 on failure so callers
+// normalizing optional/best-effort price fields don't need to thread an
+// error through for a value that's about to be re-validated anyway.
+func parseFloatOrZero(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/aphis/24hrt-backend/config"
+)
+
+// FuturesTradingClient is the futures counterpart to TradingClient. It shares
+// spot's timeSync (see NewFuturesTradingClient) so both clients agree on the
+// same clock offset, but otherwise owns its own SDK client since futures
+// trading (leverage, margin mode, hedge positions) has no spot equivalent.
+type FuturesTradingClient struct {
+	ctx       context.Context
+	client    *futures.Client
+	apiKey    string
+	secretKey string
+	isTestnet bool
+	timeSync  *timeSync
+}
+
+// NewFuturesTradingClient creates a Binance USDT-M futures client. It shares
+// spot's timeSync, starting from spot's already-synchronized offset, then
+// re-syncs against the futures server clock and stores the result in that
+// same shared timeSync, so spot and futures requests use one offset.
+func NewFuturesTradingClient(ctx context.Context, cfg *config.Config, spot *TradingClient) *FuturesTradingClient {
+	client := futures.NewClient(cfg.BinanceAPIKey, cfg.BinanceSecretKey)
+
+	if cfg.UseFuturesTestnet {
+		client.BaseURL = "https://testnet.binancefuture.com"
+		log.Println("🧪 Using Binance Futures TESTNET")
+	} else {
+		log.Println("⚠️  Using Binance Futures PRODUCTION - Real money, with leverage!")
+	}
+
+	fc := &FuturesTradingClient{
+		ctx:       ctx,
+		client:    client,
+		apiKey:    cfg.BinanceAPIKey,
+		secretKey: cfg.BinanceSecretKey,
+		isTestnet: cfg.UseFuturesTestnet,
+		timeSync:  spot.timeSync,
+	}
+
+	client.TimeOffset = fc.timeSync.Offset()
+
+	if err := fc.syncTime(); err != nil {
+		log.Printf("⚠️  Failed to sync futures time: %v", err)
+	}
+
+	return fc
+}
+
+// syncTime re-synchronizes against the futures server clock and applies the
+// refreshed offset to both timeSync (shared with spot) and the SDK client.
+func (fc *FuturesTradingClient) syncTime() error {
+	serverTime, err := fc.client.NewServerTimeService().Do(fc.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get futures server time: %w", err)
+	}
+
+	offset := fc.timeSync.apply(serverTime)
+	fc.client.TimeOffset = offset
+
+	return nil
+}
+
+// futuresSideType maps an order side string to the SDK's enum, matching
+// TradingClient.PlaceMarketOrder's "invalid order side" error convention.
+func futuresSideType(side string) (futures.SideType, error) {
+	switch side {
+	case "BUY":
+		return futures.SideTypeBuy, nil
+	case "SELL":
+		return futures.SideTypeSell, nil
+	default:
+		return "", fmt.Errorf("invalid order side: %s", side)
+	}
+}
+
+// futuresOrderResult converts an SDK order response into the shared
+// OrderResult type, leaving the spot-only fields zero-valued.
+func futuresOrderResult(order *futures.CreateOrderResponse) *OrderResult {
+	return &OrderResult{
+		OrderID:             order.OrderID,
+		Symbol:              order.Symbol,
+		Side:                string(order.Side),
+		Type:                string(order.Type),
+		Price:               order.Price,
+		Quantity:            order.OrigQuantity,
+		Status:              string(order.Status),
+		ExecutedQty:         order.ExecutedQuantity,
+		CummulativeQuoteQty: order.CumQuote,
+		ReduceOnly:          order.ReduceOnly,
+		PositionSide:        string(order.PositionSide),
+	}
+}
+
+// PlaceMarketOrder places a futures market order for symbol on the given
+// positionSide (relevant only in hedge mode; pass futures.PositionSideTypeBoth
+// for one-way mode).
+func (fc *FuturesTradingClient) PlaceMarketOrder(symbol, side, quantity string, positionSide futures.PositionSideType) (*OrderResult, error) {
+	if fc.apiKey == "" || fc.apiKey == "your_testnet_api_key_here" {
+		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
+	}
+
+	sideType, err := futuresSideType(side)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fc.syncTime(); err != nil {
+		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
+	}
+
+	order, err := fc.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(sideType).
+		PositionSide(positionSide).
+		Type(futures.OrderTypeMarket).
+		Quantity(quantity).
+		Do(fc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to place futures market order: %w", err)
+	}
+
+	result := futuresOrderResult(order)
+	log.Printf("✅ FUTURES %s Order placed: %s %s @ Market (OrderID: %d)", side, quantity, symbol, order.OrderID)
+	return result, nil
+}
+
+// PlaceLimitOrder places a futures GTC limit order for symbol on the given
+// positionSide.
+func (fc *FuturesTradingClient) PlaceLimitOrder(symbol, side, quantity, price string, positionSide futures.PositionSideType) (*OrderResult, error) {
+	if fc.apiKey == "" || fc.apiKey == "your_testnet_api_key_here" {
+		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
+	}
+
+	sideType, err := futuresSideType(side)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fc.syncTime(); err != nil {
+		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
+	}
+
+	order, err := fc.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(sideType).
+		PositionSide(positionSide).
+		Type(futures.OrderTypeLimit).
+		TimeInForce(futures.TimeInForceTypeGTC).
+		Quantity(quantity).
+		Price(price).
+		Do(fc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to place futures limit order: %w", err)
+	}
+
+	result := futuresOrderResult(order)
+	log.Printf("✅ FUTURES %s LIMIT Order placed: %s %s @ %s (OrderID: %d)", side, quantity, symbol, price, order.OrderID)
+	return result, nil
+}
+
+// SetLeverage sets the leverage for symbol. Binance applies this per-symbol,
+// not account-wide, so callers typically call it once before placing orders
+// on a symbol they haven't traded this session.
+func (fc *FuturesTradingClient) SetLeverage(symbol string, leverage int) error {
+	_, err := fc.client.NewChangeLeverageService().
+		Symbol(symbol).
+		Leverage(leverage).
+		Do(fc.ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to set leverage for %s: %w", symbol, err)
+	}
+
+	log.Printf("⚙️  Leverage for %s set to %dx", symbol, leverage)
+	return nil
+}
+
+// SetMarginType sets symbol's margin mode: isolated when isolated is true,
+// cross otherwise. Binance rejects this call while symbol has an open
+// position, so callers must flatten first if they're switching mid-trade.
+func (fc *FuturesTradingClient) SetMarginType(symbol string, isolated bool) error {
+	marginType := futures.MarginTypeCrossed
+	if isolated {
+		marginType = futures.MarginTypeIsolated
+	}
+
+	err := fc.client.NewChangeMarginTypeService().
+		Symbol(symbol).
+		MarginType(marginType).
+		Do(fc.ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to set margin type for %s: %w", symbol, err)
+	}
+
+	log.Printf("⚙️  Margin type for %s set to %s", symbol, marginType)
+	return nil
+}
+
+// SetPositionMode toggles account-wide hedge mode (independent long/short
+// positions per symbol) versus one-way mode. This affects every symbol on
+// the futures account, not just one.
+func (fc *FuturesTradingClient) SetPositionMode(hedge bool) error {
+	err := fc.client.NewChangePositionModeService().
+		DualSide(hedge).
+		Do(fc.ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to set position mode: %w", err)
+	}
+
+	log.Printf("⚙️  Position mode set to hedge=%v", hedge)
+	return nil
+}
+
+// PositionRisk describes one open (or flat) futures position.
+type PositionRisk struct {
+	Symbol           string
+	PositionSide     string
+	PositionAmt      string
+	EntryPrice       string
+	MarkPrice        string
+	UnrealizedPnL    string
+	LiquidationPrice string
+	Leverage         string
+}
+
+// GetPositionRisk retrieves current position risk for every futures symbol
+// with a position (flat symbols are included with a zero PositionAmt).
+func (fc *FuturesTradingClient) GetPositionRisk() ([]PositionRisk, error) {
+	risks, err := fc.client.NewGetPositionRiskService().Do(fc.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position risk: %w", err)
+	}
+
+	result := make([]PositionRisk, len(risks))
+	for i, r := range risks {
+		result[i] = PositionRisk{
+			Symbol:           r.Symbol,
+			PositionSide:     string(r.PositionSide),
+			PositionAmt:      r.PositionAmt,
+			EntryPrice:       r.EntryPrice,
+			MarkPrice:        r.MarkPrice,
+			UnrealizedPnL:    r.UnRealizedProfit,
+			LiquidationPrice: r.LiquidationPrice,
+			Leverage:         r.Leverage,
+		}
+	}
+
+	return result, nil
+}
+
+// FundingRate is the most recent funding rate applied to a perpetual symbol.
+type FundingRate struct {
+	Symbol      string
+	FundingRate string
+	FundingTime int64
+}
+
+// GetFundingRate returns the latest funding rate for symbol.
+func (fc *FuturesTradingClient) GetFundingRate(symbol string) (*FundingRate, error) {
+	rates, err := fc.client.NewFundingRateService().
+		Symbol(symbol).
+		Limit(1).
+		Do(fc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding rate for %s: %w", symbol, err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no funding rate data for %s", symbol)
+	}
+
+	latest := rates[len(rates)-1]
+	return &FundingRate{
+		Symbol:      symbol,
+		FundingRate: latest.FundingRate,
+		FundingTime: latest.FundingTime,
+	}, nil
+}
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/google/uuid"
+)
+
+// idempotentMaxRetries/idempotentBaseBackoff/idempotentMaxBackoff bound how
+// hard PlaceOrderIdempotent retries a transient failure before giving up.
+const (
+	idempotentMaxRetries  = 5
+	idempotentBaseBackoff = 500 * time.Millisecond
+	idempotentMaxBackoff  = 10 * time.Second
+)
+
+// OrderRequest describes an order for PlaceOrderIdempotent. Type is
+// "MARKET" or "LIMIT"; Price is only read for "LIMIT".
+type OrderRequest struct {
+	Symbol   string
+	Side     string // "BUY" or "SELL"
+	Type     string // "MARKET" or "LIMIT"
+	Quantity string
+	Price    string
+}
+
+// PlaceOrderIdempotent submits req tagged with a fresh client-generated
+// order ID, retrying transient failures (timeouts, EOF, connection resets,
+// 5xx) with exponential backoff. Before each retry it calls
+// GetOrderByClientID to check whether the previous attempt actually landed
+// on the exchange despite the error the client saw, so a retry can never
+// double-fill a position a prior "failed" request actually placed.
+func (tc *TradingClient) PlaceOrderIdempotent(req OrderRequest) (*OrderResult, error) {
+	clientOrderID := uuid.New().String()
+
+	var lastErr error
+	for attempt := 0; attempt < idempotentMaxRetries; attempt++ {
+		if attempt > 0 {
+			if existing, err := tc.GetOrderByClientID(req.Symbol, clientOrderID); err == nil && existing != nil {
+				log.Printf("♻️  Order %s already landed on a prior attempt, skipping resubmit", clientOrderID)
+				return existing, nil
+			}
+
+			delay := idempotentBackoff(attempt)
+			log.Printf("🔁 Retrying order %s after transient error (attempt %d/%d, waiting %s): %v", clientOrderID, attempt+1, idempotentMaxRetries, delay, lastErr)
+			select {
+			case <-time.After(delay):
+			case <-tc.ctx.Done():
+				return nil, tc.ctx.Err()
+			}
+		}
+
+		result, err := tc.submitOrder(req, clientOrderID)
+		if err == nil {
+			return result, nil
+		}
+
+		lastErr = err
+		if !isTransientOrderError(err) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("order %s failed after %d attempts: %w", clientOrderID, idempotentMaxRetries, lastErr)
+}
+
+// submitOrder places a single attempt of req tagged with clientOrderID,
+// going through the same rate-limit and exchange-filter normalization as
+// the Place*Order methods.
+func (tc *TradingClient) submitOrder(req OrderRequest, clientOrderID string) (*OrderResult, error) {
+	if tc.apiKey == "" || tc.apiKey == "your_testnet_api_key_here" {
+		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
+	}
+
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	sideType, err := binanceSideType(req.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tc.syncTime(); err != nil {
+		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
+	}
+
+	svc := tc.client.NewCreateOrderService().
+		Symbol(req.Symbol).
+		Side(sideType).
+		NewClientOrderID(clientOrderID)
+
+	switch req.Type {
+	case "MARKET":
+		qty, err := tc.normalizeQuantity(req.Symbol, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		svc = svc.Type(binance.OrderTypeMarket).Quantity(qty)
+
+	case "LIMIT":
+		qty, price, err := tc.normalizeLimitOrder(req.Symbol, req.Quantity, req.Price)
+		if err != nil {
+			return nil, err
+		}
+		svc = svc.Type(binance.OrderTypeLimit).TimeInForce(binance.TimeInForceTypeGTC).Quantity(qty).Price(price)
+
+	default:
+		return nil, fmt.Errorf("invalid order type: %s", req.Type)
+	}
+
+	order, err := svc.Do(tc.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	result := &OrderResult{
+		OrderID:             order.OrderID,
+		Symbol:              order.Symbol,
+		Side:                string(order.Side),
+		Type:                string(order.Type),
+		Price:               order.Price,
+		Quantity:            order.OrigQuantity,
+		Status:              string(order.Status),
+		ExecutedQty:         order.ExecutedQuantity,
+		CummulativeQuoteQty: order.CummulativeQuoteQuantity,
+	}
+
+	log.Printf("✅ %s Order placed (ClientOrderID: %s, OrderID: %d)", req.Side, clientOrderID, order.OrderID)
+	return result, nil
+}
+
+// GetOrderByClientID looks up an order by the client-generated order ID
+// passed to PlaceOrderIdempotent (or NewClientOrderID on any Place*Order
+// call), so callers can confirm whether a seemingly-failed submission
+// actually landed before resubmitting.
+func (tc *TradingClient) GetOrderByClientID(symbol, clientOrderID string) (*OrderResult, error) {
+	order, err := tc.client.NewGetOrderService().
+		Symbol(symbol).
+		OrigClientOrderID(clientOrderID).
+		Do(tc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order %s: %w", clientOrderID, err)
+	}
+
+	return &OrderResult{
+		OrderID:             order.OrderID,
+		Symbol:              order.Symbol,
+		Side:                string(order.Side),
+		Type:                string(order.Type),
+		Price:               order.Price,
+		Quantity:            order.OrigQuantity,
+		Status:              string(order.Status),
+		ExecutedQty:         order.ExecutedQuantity,
+		CummulativeQuoteQty: order.CummulativeQuoteQuantity,
+	}, nil
+}
+
+// binanceSideType maps a side string to the SDK's enum, matching
+// TradingClient.PlaceMarketOrder's "invalid order side" error convention.
+func binanceSideType(side string) (binance.SideType, error) {
+	switch side {
+	case "BUY":
+		return binance.SideTypeBuy, nil
+	case "SELL":
+		return binance.SideTypeSell, nil
+	default:
+		return "", fmt.Errorf("invalid order side: %s", side)
+	}
+}
+
+// isTransientOrderError reports whether err looks like a network-level or
+// server-side hiccup worth retrying, as opposed to a permanent rejection
+// (bad symbol, insufficient balance, invalid filter) that retrying would
+// only repeat.
+func isTransientOrderError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := err.Error()
+	for _, substr := range []string{"EOF", "timeout", "connection reset", "500 ", "502 ", "503 ", "504 "} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// idempotentBackoff returns the exponential backoff delay for attempt
+// (0-indexed), capped at idempotentMaxBackoff, with ±20% jitter applied
+// after the cap.
+func idempotentBackoff(attempt int) time.Duration {
+	delay := idempotentBaseBackoff
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= idempotentMaxBackoff {
+			delay = idempotentMaxBackoff
+			break
+		}
+	}
+
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(delay))
+	delay += jitter
+	if delay < 0 {
+		delay = idempotentBaseBackoff
+	}
+	return delay
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Binance documents a 1200-weight-per-minute budget for spot REST endpoints.
+// backOffThreshold is the fraction of that budget past which waitForRateLimit
+// proactively slows down instead of waiting for a -1003 Too Many Requests ban.
+const (
+	spotWeightLimit1m = 1200
+	backOffThreshold  = 0.8
+)
+
+// RateLimitUsage is a snapshot of the most recently observed rate limit
+// headers, for callers (e.g. the strategy layer) deciding whether to back
+// off further on their own.
+type RateLimitUsage struct {
+	UsedWeight1m    int
+	RemainingWeight int
+	OrderCounts     map[string]int // interval (e.g. "10s", "1d") -> count, from X-MBX-ORDER-COUNT-*
+}
+
+// rateLimitState tracks the most recent X-MBX-USED-WEIGHT-1M and
+// X-MBX-ORDER-COUNT-* response headers, updated by rateLimitTransport on
+// every response that passes through it.
+type rateLimitState struct {
+	mu           sync.RWMutex
+	usedWeight1m int
+	orderCounts  map[string]int
+}
+
+func (s *rateLimitState) update(header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v := header.Get("X-Mbx-Used-Weight-1m"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			s.usedWeight1m = n
+		}
+	}
+
+	for key, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		lower := strings.ToLower(key)
+		if !strings.HasPrefix(lower, "x-mbx-order-count-") {
+			continue
+		}
+		if n, err := strconv.Atoi(values[0]); err == nil {
+			if s.orderCounts == nil {
+				s.orderCounts = make(map[string]int)
+			}
+			s.orderCounts[strings.TrimPrefix(lower, "x-mbx-order-count-")] = n
+		}
+	}
+}
+
+// snapshot returns a copy safe for callers to read without holding a lock.
+func (s *rateLimitState) snapshot() RateLimitUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int, len(s.orderCounts))
+	for k, v := range s.orderCounts {
+		counts[k] = v
+	}
+
+	remaining := spotWeightLimit1m - s.usedWeight1m
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return RateLimitUsage{
+		UsedWeight1m:    s.usedWeight1m,
+		RemainingWeight: remaining,
+		OrderCounts:     counts,
+	}
+}
+
+// overBudget reports whether used weight has crossed backOffThreshold of
+// the documented per-minute weight budget.
+func (s *rateLimitState) overBudget() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return float64(s.usedWeight1m) >= spotWeightLimit1m*backOffThreshold
+}
+
+// rateLimitTransport wraps an http.RoundTripper, recording Binance's
+// X-MBX-* rate-limit headers from every response before handing it back
+// to the caller unchanged.
+type rateLimitTransport struct {
+	next  http.RoundTripper
+	state *rateLimitState
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	t.state.update(resp.Header)
+	return resp, nil
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrBelowMinNotional is returned when an order's price*quantity falls below
+// the symbol's exchange-info-reported minimum notional, even after rounding.
+var ErrBelowMinNotional = errors.New("order value is below the symbol's minimum notional")
+
+// ErrInvalidLotSize is returned when a quantity rounds down to below the
+// symbol's minimum lot size (or above its maximum).
+var ErrInvalidLotSize = errors.New("quantity is outside the symbol's allowed lot size")
+
+// SymbolFilters holds the exchange-info trading rules LoadSymbolFilters
+// caches per symbol, used by FormatPrice/FormatQuantity to round orders onto
+// an allowed increment before they're submitted.
+type SymbolFilters struct {
+	TickSize          float64
+	StepSize          float64
+	MinQty            float64
+	MaxQty            float64
+	MinNotional       float64
+	PricePrecision    int
+	QuantityPrecision int
+}
+
+// LoadSymbolFilters fetches exchange info and caches PRICE_FILTER, LOT_SIZE,
+// and MIN_NOTIONAL rules per symbol. Call it once at startup (or whenever the
+// tradeable symbol set changes) before relying on FormatPrice/FormatQuantity
+// — until it's called, those helpers pass values through unrounded.
+func (tc *TradingClient) LoadSymbolFilters() error {
+	info, err := tc.client.NewExchangeInfoService().Do(tc.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load exchange info: %w", err)
+	}
+
+	filters := make(map[string]SymbolFilters, len(info.Symbols))
+	for _, sym := range info.Symbols {
+		var sf SymbolFilters
+
+		if pf := sym.PriceFilter(); pf != nil {
+			sf.TickSize, _ = strconv.ParseFloat(pf.TickSize, 64)
+			sf.PricePrecision = decimalPrecision(pf.TickSize)
+		}
+		if lf := sym.LotSizeFilter(); lf != nil {
+			sf.StepSize, _ = strconv.ParseFloat(lf.StepSize, 64)
+			sf.MinQty, _ = strconv.ParseFloat(lf.MinQuantity, 64)
+			sf.MaxQty, _ = strconv.ParseFloat(lf.MaxQuantity, 64)
+			sf.QuantityPrecision = decimalPrecision(lf.StepSize)
+		}
+		if mn := sym.NotionalFilter(); mn != nil {
+			sf.MinNotional, _ = strconv.ParseFloat(mn.MinNotional, 64)
+		}
+
+		filters[sym.Symbol] = sf
+	}
+
+	tc.filtersMu.Lock()
+	tc.filters = filters
+	tc.filtersMu.Unlock()
+
+	log.Printf("📐 Loaded exchange filters for %d symbols", len(filters))
+	return nil
+}
+
+// decimalPrecision returns the number of digits after the decimal point
+// needed to represent raw exactly, e.g. "0.00100000" -> 3. Exchange filter
+// values are always powers of ten with trailing zeros, so trimming them is
+// enough to recover the intended precision.
+func decimalPrecision(raw string) int {
+	trimmed := strings.TrimRight(raw, "0")
+	idx := strings.Index(trimmed, ".")
+	if idx == -1 {
+		return 0
+	}
+	return len(trimmed) - idx - 1
+}
+
+// symbolFilters returns symbol's cached filters, or a zero-value
+// SymbolFilters if LoadSymbolFilters hasn't been called or doesn't know the
+// symbol yet.
+func (tc *TradingClient) symbolFilters(symbol string) SymbolFilters {
+	tc.filtersMu.RLock()
+	defer tc.filtersMu.RUnlock()
+	return tc.filters[symbol]
+}
+
+// FormatPrice rounds price down to the nearest tickSize for symbol and
+// formats it at the matching precision. If filters haven't been loaded for
+// symbol, price is formatted as-is.
+func (tc *TradingClient) FormatPrice(symbol string, price float64) string {
+	sf := tc.symbolFilters(symbol)
+	if sf.TickSize <= 0 {
+		return strconv.FormatFloat(price, 'f', -1, 64)
+	}
+
+	rounded := math.Floor(price/sf.TickSize) * sf.TickSize
+	return strconv.FormatFloat(rounded, 'f', sf.PricePrecision, 64)
+}
+
+// FormatQuantity rounds qty down to the nearest stepSize for symbol and
+// formats it at the matching precision. If filters haven't been loaded for
+// symbol, qty is formatted as-is.
+func (tc *TradingClient) FormatQuantity(symbol string, qty float64) string {
+	sf := tc.symbolFilters(symbol)
+	if sf.StepSize <= 0 {
+		return strconv.FormatFloat(qty, 'f', -1, 64)
+	}
+
+	rounded := math.Floor(qty/sf.StepSize) * sf.StepSize
+	return strconv.FormatFloat(rounded, 'f', sf.QuantityPrecision, 64)
+}
+
+// validateLotSize reports ErrInvalidLotSize if qty (already rounded to
+// stepSize) falls outside symbol's [minQty, maxQty] range.
+func (tc *TradingClient) validateLotSize(symbol string, qty float64) error {
+	sf := tc.symbolFilters(symbol)
+	if sf.MinQty > 0 && qty < sf.MinQty {
+		return ErrInvalidLotSize
+	}
+	if sf.MaxQty > 0 && qty > sf.MaxQty {
+		return ErrInvalidLotSize
+	}
+	return nil
+}
+
+// validateNotional reports ErrBelowMinNotional if price*qty (already
+// rounded) falls below symbol's minNotional.
+func (tc *TradingClient) validateNotional(symbol string, price, qty float64) error {
+	sf := tc.symbolFilters(symbol)
+	if sf.MinNotional > 0 && price*qty < sf.MinNotional {
+		return ErrBelowMinNotional
+	}
+	return nil
+}
+
+// normalizeQuantity parses quantity, rounds it down to symbol's stepSize,
+// and validates the result against minQty/maxQty before returning it ready
+// for submission.
+func (tc *TradingClient) normalizeQuantity(symbol, quantity string) (string, error) {
+	qty, err := strconv.ParseFloat(quantity, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid quantity %q: %w", quantity, err)
+	}
+
+	rounded := tc.FormatQuantity(symbol, qty)
+	roundedQty, _ := strconv.ParseFloat(rounded, 64)
+	if err := tc.validateLotSize(symbol, roundedQty); err != nil {
+		return "", err
+	}
+
+	return rounded, nil
+}
+
+// normalizeLimitOrder parses and rounds both quantity and price for a limit
+// order, validating lot size and minimum notional against the rounded
+// values so the caller never submits a request Binance would reject with
+// PRICE_FILTER, LOT_SIZE, or MIN_NOTIONAL.
+func (tc *TradingClient) normalizeLimitOrder(symbol, quantity, price string) (roundedQty, roundedPrice string, err error) {
+	qty, err := strconv.ParseFloat(quantity, 64)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid quantity %q: %w", quantity, err)
+	}
+	prc, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid price %q: %w", price, err)
+	}
+
+	roundedQty = tc.FormatQuantity(symbol, qty)
+	roundedPrice = tc.FormatPrice(symbol, prc)
+
+	roundedQtyF, _ := strconv.ParseFloat(roundedQty, 64)
+	roundedPriceF, _ := strconv.ParseFloat(roundedPrice, 64)
+
+	if err := tc.validateLotSize(symbol, roundedQtyF); err != nil {
+		return "", "", err
+	}
+	if err := tc.validateNotional(symbol, roundedPriceF, roundedQtyF); err != nil {
+		return "", "", err
+	}
+
+	return roundedQty, roundedPrice, nil
+}
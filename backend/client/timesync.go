@@ -0,0 +1,35 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// timeSync tracks the clock offset against Binance server time. It's shared
+// between TradingClient and FuturesTradingClient so spot and futures requests
+// are corrected against the same offset even though each owns its own SDK
+// client (*binance.Client / *futures.Client).
+type timeSync struct {
+	mu     sync.RWMutex
+	offset int64
+}
+
+// apply recomputes the offset from a freshly-fetched server time and stores
+// it, returning the new offset for callers that want to log it immediately.
+func (ts *timeSync) apply(serverTimeMs int64) int64 {
+	localTimeUTC := time.Now().UTC().UnixNano() / int64(time.Millisecond)
+	offset := serverTimeMs - localTimeUTC - 5000
+
+	ts.mu.Lock()
+	ts.offset = offset
+	ts.mu.Unlock()
+
+	return offset
+}
+
+// Offset returns the most recently computed clock offset in milliseconds.
+func (ts *timeSync) Offset() int64 {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return ts.offset
+}
@@ -4,25 +4,42 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
 	"github.com/aphis/24hrt-backend/config"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
 )
 
+// orderRateLimit matches Binance's documented order-placement limits: 5
+// requests/sec with a burst of 2, tracked locally so the strategy layer
+// above can't spam orders into a -1003 Too Many Requests ban.
+var orderRateLimit = rate.Limit(5)
+
 type TradingClient struct {
+	ctx       context.Context
 	client    *binance.Client
 	apiKey    string
 	secretKey string
 	isTestnet bool
-	timeOffset int64 // Cache server time offset
+	timeSync  *timeSync // Shared with FuturesTradingClient so both clocks stay in lockstep
+	limiter   *rate.Limiter
+	rateLimit *rateLimitState
+
+	filtersMu sync.RWMutex
+	filters   map[string]SymbolFilters // Populated by LoadSymbolFilters; see symbolfilters.go
 }
 
-// NewTradingClient creates a new Binance trading client
-func NewTradingClient(cfg *config.Config) *TradingClient {
+// NewTradingClient creates a new Binance trading client. ctx scopes every
+// outbound request the client makes and is honored for cancellation during
+// shutdown; callers should pass a context tied to the process lifetime.
+func NewTradingClient(ctx context.Context, cfg *config.Config) *TradingClient {
 	client := binance.NewClient(cfg.BinanceAPIKey, cfg.BinanceSecretKey)
-	
+
 	// Set testnet base URL if testnet mode is enabled
 	if cfg.UseTestnet {
 		client.BaseURL = "https://testnet.binance.vision"
@@ -31,11 +48,25 @@ func NewTradingClient(cfg *config.Config) *TradingClient {
 		log.Println("⚠️  Using Binance PRODUCTION - Real money!")
 	}
 
+	rlState := &rateLimitState{}
+	if client.HTTPClient == nil {
+		client.HTTPClient = &http.Client{}
+	}
+	baseTransport := client.HTTPClient.Transport
+	if baseTransport == nil {
+		baseTransport = http.DefaultTransport
+	}
+	client.HTTPClient.Transport = &rateLimitTransport{next: baseTransport, state: rlState}
+
 	tc := &TradingClient{
+		ctx:       ctx,
 		client:    client,
 		apiKey:    cfg.BinanceAPIKey,
 		secretKey: cfg.BinanceSecretKey,
 		isTestnet: cfg.UseTestnet,
+		timeSync:  &timeSync{},
+		limiter:   rate.NewLimiter(orderRateLimit, 2),
+		rateLimit: rlState,
 	}
 
 	// Sync time with server and set time offset in the client BEFORE any API calls
@@ -43,13 +74,19 @@ func NewTradingClient(cfg *config.Config) *TradingClient {
 		log.Printf("⚠️  Failed to sync time: %v", err)
 	}
 
+	// Load PRICE_FILTER/LOT_SIZE/MIN_NOTIONAL rules so FormatPrice/FormatQuantity
+	// round orders onto an allowed increment instead of passing them through raw.
+	if err := tc.LoadSymbolFilters(); err != nil {
+		log.Printf("⚠️  Failed to load symbol filters: %v", err)
+	}
+
 	return tc
 }
 
 // syncTimeAndApply synchronizes with server and immediately applies the offset
 func (tc *TradingClient) syncTimeAndApply() error {
 	// Get server time first (Binance returns UTC time in milliseconds)
-	serverTime, err := tc.client.NewServerTimeService().Do(context.Background())
+	serverTime, err := tc.client.NewServerTimeService().Do(tc.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get server time: %w", err)
 	}
@@ -57,24 +94,22 @@ func (tc *TradingClient) syncTimeAndApply() error {
 	// IMPORTANT: Must use UTC time for comparison with Binance server
 	// time.Now() returns local time, so we must explicitly use UTC()
 	localTimeUTC := time.Now().UTC().UnixNano() / int64(time.Millisecond)
-	
-	// Calculate raw offset
 	actualOffset := serverTime - localTimeUTC
-	
-	// Add safety buffer to ensure we're never ahead of server time
-	// This prevents timestamp errors (-5 seconds buffer)
-	tc.timeOffset = actualOffset - 5000
-	
+
+	// apply adds the same -5000ms safety buffer this client always has, to
+	// ensure we're never ahead of server time (which causes timestamp errors).
+	appliedOffset := tc.timeSync.apply(serverTime)
+
 	// Apply to client
-	tc.client.TimeOffset = tc.timeOffset
-	
+	tc.client.TimeOffset = appliedOffset
+
 	log.Printf("🕐 Time synchronized (using UTC):")
 	log.Printf("   Server time (UTC): %d", serverTime)
 	log.Printf("   Local time (UTC):  %d", localTimeUTC)
 	log.Printf("   System timezone:   %s", time.Now().Location().String())
 	log.Printf("   Raw offset:        %dms", actualOffset)
-	log.Printf("   Applied offset:    %dms (with -5000ms safety buffer)", tc.timeOffset)
-	
+	log.Printf("   Applied offset:    %dms (with -5000ms safety buffer)", appliedOffset)
+
 	return nil
 }
 
@@ -86,20 +121,55 @@ func (tc *TradingClient) syncTime() error {
 // getServerTime returns synchronized server time
 func (tc *TradingClient) getServerTime() int64 {
 	localTime := time.Now().UnixNano() / int64(time.Millisecond)
-	return localTime + tc.timeOffset
+	return localTime + tc.timeSync.Offset()
+}
+
+// waitForRateLimit blocks until the local token bucket allows another
+// request, then applies an additional fixed back-off if the last observed
+// X-MBX-USED-WEIGHT-1M crossed backOffThreshold of Binance's documented
+// per-minute weight budget. The token bucket alone only smooths our own
+// request rate; this second check is what actually prevents -1003 Too Many
+// Requests bans driven by exchange-observed weight.
+func (tc *TradingClient) waitForRateLimit() error {
+	if err := tc.limiter.Wait(tc.ctx); err != nil {
+		return fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+
+	if tc.rateLimit.overBudget() {
+		log.Printf("⏳ Rate limit usage over %.0f%% of budget, backing off", backOffThreshold*100)
+		select {
+		case <-time.After(2 * time.Second):
+		case <-tc.ctx.Done():
+			return tc.ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// GetRateLimitUsage returns the most recently observed weight/order-count
+// usage, parsed from Binance's X-MBX-* response headers.
+func (tc *TradingClient) GetRateLimitUsage() RateLimitUsage {
+	return tc.rateLimit.snapshot()
 }
 
 // OrderResult contains order execution details
 type OrderResult struct {
-	OrderID       int64
-	Symbol        string
-	Side          string
-	Type          string
-	Price         string
-	Quantity      string
-	Status        string
-	ExecutedQty   string
+	OrderID             int64
+	Symbol              string
+	Side                string
+	Type                string
+	Price               string
+	Quantity            string
+	Status              string
+	ExecutedQty         string
 	CummulativeQuoteQty string
+
+	// Futures-only fields, left zero-valued for spot orders.
+	ReduceOnly   bool
+	PositionSide string
+	RealizedPnL  string
+	MarkPrice    string
 }
 
 // PlaceMarketBuyOrder places a market buy order
@@ -109,6 +179,15 @@ func (tc *TradingClient) PlaceMarketBuyOrder(symbol string, quantity string) (*O
 		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
 	}
 
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	normalizedQty, err := tc.normalizeQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resync time before placing order
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
@@ -118,8 +197,9 @@ func (tc *TradingClient) PlaceMarketBuyOrder(symbol string, quantity string) (*O
 		Symbol(symbol).
 		Side(binance.SideTypeBuy).
 		Type(binance.OrderTypeMarket).
-		Quantity(quantity).
-		Do(context.Background())
+		NewClientOrderID(uuid.New().String()).
+		Quantity(normalizedQty).
+		Do(tc.ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to place buy order: %w", err)
@@ -148,6 +228,15 @@ func (tc *TradingClient) PlaceMarketSellOrder(symbol string, quantity string) (*
 		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
 	}
 
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	normalizedQty, err := tc.normalizeQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resync time before placing order
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
@@ -157,8 +246,9 @@ func (tc *TradingClient) PlaceMarketSellOrder(symbol string, quantity string) (*
 		Symbol(symbol).
 		Side(binance.SideTypeSell).
 		Type(binance.OrderTypeMarket).
-		Quantity(quantity).
-		Do(context.Background())
+		NewClientOrderID(uuid.New().String()).
+		Quantity(normalizedQty).
+		Do(tc.ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to place sell order: %w", err)
@@ -187,6 +277,15 @@ func (tc *TradingClient) PlaceLimitBuyOrder(symbol string, quantity string, pric
 		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
 	}
 
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	normalizedQty, normalizedPrice, err := tc.normalizeLimitOrder(symbol, quantity, price)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resync time before placing order
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
@@ -197,9 +296,10 @@ func (tc *TradingClient) PlaceLimitBuyOrder(symbol string, quantity string, pric
 		Side(binance.SideTypeBuy).
 		Type(binance.OrderTypeLimit).
 		TimeInForce(binance.TimeInForceTypeGTC).
-		Quantity(quantity).
-		Price(price).
-		Do(context.Background())
+		NewClientOrderID(uuid.New().String()).
+		Quantity(normalizedQty).
+		Price(normalizedPrice).
+		Do(tc.ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to place limit buy order: %w", err)
@@ -226,6 +326,15 @@ func (tc *TradingClient) PlaceLimitSellOrder(symbol string, quantity string, pri
 		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
 	}
 
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
+	normalizedQty, normalizedPrice, err := tc.normalizeLimitOrder(symbol, quantity, price)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resync time before placing order
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
@@ -236,9 +345,10 @@ func (tc *TradingClient) PlaceLimitSellOrder(symbol string, quantity string, pri
 		Side(binance.SideTypeSell).
 		Type(binance.OrderTypeLimit).
 		TimeInForce(binance.TimeInForceTypeGTC).
-		Quantity(quantity).
-		Price(price).
-		Do(context.Background())
+		NewClientOrderID(uuid.New().String()).
+		Quantity(normalizedQty).
+		Price(normalizedPrice).
+		Do(tc.ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to place limit sell order: %w", err)
@@ -260,6 +370,10 @@ func (tc *TradingClient) PlaceLimitSellOrder(symbol string, quantity string, pri
 
 // CancelOrder cancels an existing order
 func (tc *TradingClient) CancelOrder(symbol string, orderID int64) error {
+	if err := tc.waitForRateLimit(); err != nil {
+		return err
+	}
+
 	// Resync time before canceling order
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
@@ -268,7 +382,7 @@ func (tc *TradingClient) CancelOrder(symbol string, orderID int64) error {
 	_, err := tc.client.NewCancelOrderService().
 		Symbol(symbol).
 		OrderID(orderID).
-		Do(context.Background())
+		Do(tc.ctx)
 
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
@@ -292,13 +406,17 @@ func (tc *TradingClient) GetAccountBalance() ([]BalanceInfo, error) {
 		return nil, fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
 	}
 
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
 	// Resync time before fetching balance to avoid timestamp errors
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
 	}
 
 	// Use RecvWindow to allow for more timestamp tolerance (10 seconds)
-	account, err := tc.client.NewGetAccountService().Do(context.Background())
+	account, err := tc.client.NewGetAccountService().Do(tc.ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account info: %w", err)
 	}
@@ -330,6 +448,10 @@ func (tc *TradingClient) PlaceMarketOrder(symbol, side, quantity string) (*Order
 
 // GetOpenOrders retrieves all open orders for a symbol
 func (tc *TradingClient) GetOpenOrders(symbol string) ([]*OrderResult, error) {
+	if err := tc.waitForRateLimit(); err != nil {
+		return nil, err
+	}
+
 	// Resync time before fetching orders
 	if err := tc.syncTime(); err != nil {
 		log.Printf("⚠️  Time sync failed, proceeding anyway: %v", err)
@@ -337,7 +459,7 @@ func (tc *TradingClient) GetOpenOrders(symbol string) ([]*OrderResult, error) {
 
 	orders, err := tc.client.NewListOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(tc.ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
@@ -360,9 +482,55 @@ func (tc *TradingClient) GetOpenOrders(symbol string) ([]*OrderResult, error) {
 	return results, nil
 }
 
+// IsTestnet reports whether this client is pointed at Binance's testnet,
+// so dependent streamers (e.g. userstream.UserStreamer) can pick the
+// matching WebSocket base URL.
+func (tc *TradingClient) IsTestnet() bool {
+	return tc.isTestnet
+}
+
+// CreateListenKey starts a new User Data Stream and returns its listen key,
+// used to open wss://.../ws/<listenKey> for account/order/balance updates.
+func (tc *TradingClient) CreateListenKey() (string, error) {
+	if tc.apiKey == "" || tc.apiKey == "your_testnet_api_key_here" {
+		return "", fmt.Errorf("API keys not configured. Please set up Binance testnet API keys")
+	}
+
+	listenKey, err := tc.client.NewStartUserStreamService().Do(tc.ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create listen key: %w", err)
+	}
+
+	log.Println("🔑 Created user data stream listen key")
+	return listenKey, nil
+}
+
+// KeepAliveListenKey extends a listen key's 60-minute validity window.
+// Binance recommends calling this at least every 30 minutes.
+func (tc *TradingClient) KeepAliveListenKey(listenKey string) error {
+	err := tc.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(tc.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to keep listen key alive: %w", err)
+	}
+
+	log.Println("💓 Kept user data stream listen key alive")
+	return nil
+}
+
+// CloseListenKey explicitly closes a user data stream.
+func (tc *TradingClient) CloseListenKey(listenKey string) error {
+	err := tc.client.NewCloseUserStreamService().ListenKey(listenKey).Do(tc.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to close listen key: %w", err)
+	}
+
+	log.Println("🔒 Closed user data stream listen key")
+	return nil
+}
+
 // TestConnectivity tests connection to Binance API
 func (tc *TradingClient) TestConnectivity() error {
-	err := tc.client.NewPingService().Do(context.Background())
+	err := tc.client.NewPingService().Do(tc.ctx)
 	if err != nil {
 		return fmt.Errorf("connectivity test failed: %w", err)
 	}
@@ -373,7 +541,7 @@ func (tc *TradingClient) TestConnectivity() error {
 
 // GetServerTime gets Binance server time (useful for debugging time sync issues)
 func (tc *TradingClient) GetServerTime() (int64, error) {
-	serverTime, err := tc.client.NewServerTimeService().Do(context.Background())
+	serverTime, err := tc.client.NewServerTimeService().Do(tc.ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get server time: %w", err)
 	}
@@ -384,17 +552,17 @@ func (tc *TradingClient) GetServerTime() (int64, error) {
 
 // KlineData represents candlestick data
 type KlineData struct {
-	OpenTime                 int64   `json:"openTime"`
-	Open                     string  `json:"open"`
-	High                     string  `json:"high"`
-	Low                      string  `json:"low"`
-	Close                    string  `json:"close"`
-	Volume                   string  `json:"volume"`
-	CloseTime                int64   `json:"closeTime"`
-	QuoteAssetVolume         string  `json:"quoteAssetVolume"`
-	NumberOfTrades           int     `json:"numberOfTrades"`
-	TakerBuyBaseAssetVolume  string  `json:"takerBuyBaseAssetVolume"`
-	TakerBuyQuoteAssetVolume string  `json:"takerBuyQuoteAssetVolume"`
+	OpenTime                 int64  `json:"openTime"`
+	Open                     string `json:"open"`
+	High                     string `json:"high"`
+	Low                      string `json:"low"`
+	Close                    string `json:"close"`
+	Volume                   string `json:"volume"`
+	CloseTime                int64  `json:"closeTime"`
+	QuoteAssetVolume         string `json:"quoteAssetVolume"`
+	NumberOfTrades           int    `json:"numberOfTrades"`
+	TakerBuyBaseAssetVolume  string `json:"takerBuyBaseAssetVolume"`
+	TakerBuyQuoteAssetVolume string `json:"takerBuyQuoteAssetVolume"`
 }
 
 // SymbolPrice represents symbol price information
@@ -405,7 +573,7 @@ type SymbolPrice struct {
 
 // GetSymbolPrices fetches all symbol prices from Binance
 func (tc *TradingClient) GetSymbolPrices() ([]SymbolPrice, error) {
-	prices, err := tc.client.NewListPricesService().Do(context.Background())
+	prices, err := tc.client.NewListPricesService().Do(tc.ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get symbol prices: %w", err)
 	}
@@ -433,7 +601,43 @@ func (tc *TradingClient) GetKlines(symbol, interval, limitStr string) ([]KlineDa
 		Symbol(symbol).
 		Interval(interval).
 		Limit(limit).
-		Do(context.Background())
+		Do(tc.ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	result := make([]KlineData, len(klines))
+	for i, k := range klines {
+		result[i] = KlineData{
+			OpenTime:                 k.OpenTime,
+			Open:                     k.Open,
+			High:                     k.High,
+			Low:                      k.Low,
+			Close:                    k.Close,
+			Volume:                   k.Volume,
+			CloseTime:                k.CloseTime,
+			QuoteAssetVolume:         k.QuoteAssetVolume,
+			NumberOfTrades:           int(k.TradeNum),
+			TakerBuyBaseAssetVolume:  k.TakerBuyBaseAssetVolume,
+			TakerBuyQuoteAssetVolume: k.TakerBuyQuoteAssetVolume,
+		}
+	}
+
+	log.Printf("📊 Fetched %d klines for %s (%s)", len(result), symbol, interval)
+	return result, nil
+}
+
+// GetKlinesWithContext fetches historical candlestick data bound to ctx
+// rather than the client's own background context, for callers like
+// KlineStreamer.Backfill that want a caller-scoped cancellation instead of
+// running for the lifetime of the process.
+func (tc *TradingClient) GetKlinesWithContext(ctx context.Context, symbol, interval string, limit int) ([]KlineData, error) {
+	klines, err := tc.client.NewKlinesService().
+		Symbol(symbol).
+		Interval(interval).
+		Limit(limit).
+		Do(ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get klines: %w", err)
@@ -484,7 +688,7 @@ func (tc *TradingClient) GetKlinesWithTimeRange(symbol, interval, startTime, end
 		StartTime(start).
 		EndTime(end).
 		Limit(limit).
-		Do(context.Background())
+		Do(tc.ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get klines with time range: %w", err)
@@ -507,8 +711,8 @@ func (tc *TradingClient) GetKlinesWithTimeRange(symbol, interval, startTime, end
 		}
 	}
 
-	log.Printf("📊 Fetched %d klines for %s (%s) from %s to %s", 
-		len(result), symbol, interval, 
+	log.Printf("📊 Fetched %d klines for %s (%s) from %s to %s",
+		len(result), symbol, interval,
 		time.Unix(start/1000, 0).Format("2006-01-02 15:04:05"),
 		time.Unix(end/1000, 0).Format("2006-01-02 15:04:05"))
 	return result, nil
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/aphis/24hrt-backend/userstream"
+)
+
+// OrderUpdate is a parsed `executionReport` event from the user data stream.
+type OrderUpdate = userstream.OrderUpdate
+
+// UserDataStream is a callback-driven wrapper around userstream.UserStreamer
+// for callers that want to register handlers (OnOrderUpdate/OnBalanceUpdate)
+// instead of draining its channels directly — the listen-key lifecycle,
+// reconnect-with-backoff, and event decoding all stay in package userstream;
+// this just adapts its channel output to callbacks for the client package's
+// consumers. Multiple handlers can be registered (e.g. PlaceBracket adds its
+// own fill listener alongside whatever the strategy layer registered), so
+// registering one never clobbers another; each registration returns an
+// unsubscribe func so a handler that's only needed for one event doesn't
+// leak for the rest of the process's life.
+type UserDataStream struct {
+	streamer *userstream.UserStreamer
+	done     chan struct{}
+
+	handlersMu       sync.Mutex
+	nextHandlerID    int
+	onOrderUpdates   map[int]func(OrderUpdate)
+	onBalanceUpdates map[int]func(BalanceInfo)
+}
+
+// NewUserDataStream creates a UserDataStream backed by tc's listen-key
+// lifecycle (CreateListenKey/KeepAliveListenKey/CloseListenKey) and testnet
+// setting.
+func NewUserDataStream(tc *TradingClient) *UserDataStream {
+	return &UserDataStream{
+		streamer:         userstream.NewUserStreamer(tc, tc.isTestnet),
+		done:             make(chan struct{}),
+		onOrderUpdates:   make(map[int]func(OrderUpdate)),
+		onBalanceUpdates: make(map[int]func(BalanceInfo)),
+	}
+}
+
+// OnOrderUpdate registers fn to be called for every executionReport event.
+// Safe to call before or after Start; fn is added alongside any previously
+// registered handlers rather than replacing them. The returned func removes
+// fn; callers that only need one event (e.g. PlaceBracket watching for its
+// entry order to fill) should call it once fn has done its job.
+func (uds *UserDataStream) OnOrderUpdate(fn func(OrderUpdate)) func() {
+	uds.handlersMu.Lock()
+	defer uds.handlersMu.Unlock()
+
+	id := uds.nextHandlerID
+	uds.nextHandlerID++
+	uds.onOrderUpdates[id] = fn
+
+	return func() {
+		uds.handlersMu.Lock()
+		defer uds.handlersMu.Unlock()
+		delete(uds.onOrderUpdates, id)
+	}
+}
+
+// OnBalanceUpdate registers fn to be called for every balance change
+// (outboundAccountPosition entries). Safe to call before or after Start. The
+// returned func removes fn, same as OnOrderUpdate's.
+func (uds *UserDataStream) OnBalanceUpdate(fn func(BalanceInfo)) func() {
+	uds.handlersMu.Lock()
+	defer uds.handlersMu.Unlock()
+
+	id := uds.nextHandlerID
+	uds.nextHandlerID++
+	uds.onBalanceUpdates[id] = fn
+
+	return func() {
+		uds.handlersMu.Lock()
+		defer uds.handlersMu.Unlock()
+		delete(uds.onBalanceUpdates, id)
+	}
+}
+
+// Start begins the underlying user data stream and dispatches events to
+// whichever callbacks were registered via OnOrderUpdate/OnBalanceUpdate.
+// This lets the strategy layer react to fills and balance changes
+// event-driven, instead of polling GetOpenOrders/GetAccountBalance on a
+// timer.
+func (uds *UserDataStream) Start() error {
+	if err := uds.streamer.Start(); err != nil {
+		return err
+	}
+
+	go uds.dispatch()
+	return nil
+}
+
+// Stop tears down the underlying stream and its listen key.
+func (uds *UserDataStream) Stop() {
+	uds.streamer.Stop()
+	close(uds.done)
+}
+
+func (uds *UserDataStream) dispatch() {
+	orders := uds.streamer.GetOrderUpdateChannel()
+	balances := uds.streamer.GetBalanceUpdateChannel()
+
+	for {
+		select {
+		case <-uds.done:
+			return
+		case update := <-orders:
+			uds.handlersMu.Lock()
+			handlers := make([]func(OrderUpdate), 0, len(uds.onOrderUpdates))
+			for _, fn := range uds.onOrderUpdates {
+				handlers = append(handlers, fn)
+			}
+			uds.handlersMu.Unlock()
+			for _, fn := range handlers {
+				fn(update)
+			}
+		case balance := <-balances:
+			info := BalanceInfo{Asset: balance.Asset, Free: balance.Free, Locked: balance.Locked}
+			uds.handlersMu.Lock()
+			handlers := make([]func(BalanceInfo), 0, len(uds.onBalanceUpdates))
+			for _, fn := range uds.onBalanceUpdates {
+				handlers = append(handlers, fn)
+			}
+			uds.handlersMu.Unlock()
+			for _, fn := range handlers {
+				fn(info)
+			}
+		}
+	}
+}
@@ -10,12 +10,24 @@ import (
 )
 
 type Config struct {
-	BinanceAPIKey      string
-	BinanceSecretKey   string
-	UseTestnet         bool
-	DefaultSymbol      string
-	DefaultSymbols     []string
-	WSReconnectDelay   int
+	BinanceAPIKey    string
+	BinanceSecretKey string
+	UseTestnet       bool
+	DefaultSymbol    string
+	DefaultSymbols   []string
+	WSReconnectDelay int
+	MaxCandles       int // Size of the in-memory rolling kline window per symbol/interval
+
+	// Futures trading, off by default since it trades with leverage against
+	// a separate account balance from spot.
+	UseFutures        bool
+	UseFuturesTestnet bool
+	DefaultLeverage   int  // Applied to DefaultSymbol via FuturesTradingClient.SetLeverage at startup when UseFutures is set
+	DefaultIsolated   bool // Margin mode applied to DefaultSymbol via FuturesTradingClient.SetMarginType at startup; false = cross margin
+
+	// ExchangeName selects the exchange.Registry default adapter for
+	// requests that omit ?exchange=; see exchange.NewRegistry.
+	ExchangeName string
 }
 
 var AppConfig *Config
@@ -29,7 +41,14 @@ func Load() *Config {
 
 	useTestnet, _ := strconv.ParseBool(getEnv("BINANCE_USE_TESTNET", "true"))
 	wsReconnect, _ := strconv.Atoi(getEnv("WS_RECONNECT_DELAY", "5"))
-	
+	maxCandles, _ := strconv.Atoi(getEnv("MAX_CANDLES", "1000"))
+
+	useFutures, _ := strconv.ParseBool(getEnv("BINANCE_USE_FUTURES", "false"))
+	useFuturesTestnet, _ := strconv.ParseBool(getEnv("BINANCE_USE_FUTURES_TESTNET", "true"))
+	defaultLeverage, _ := strconv.Atoi(getEnv("FUTURES_DEFAULT_LEVERAGE", "1"))
+	defaultIsolated, _ := strconv.ParseBool(getEnv("FUTURES_DEFAULT_ISOLATED", "false"))
+	exchangeName := getEnv("EXCHANGE_NAME", "binance")
+
 	// Parse default symbols from comma-separated string
 	symbolsStr := getEnv("DEFAULT_SYMBOLS", "BTCUSDT,ETHUSDT,BNBUSDT,SOLUSDT,ADAUSDT,XRPUSDT,DOTUSDT,DOGEUSDT,MATICUSDT")
 	defaultSymbols := strings.Split(symbolsStr, ",")
@@ -38,12 +57,18 @@ func Load() *Config {
 	}
 
 	AppConfig = &Config{
-		BinanceAPIKey:    getEnv("BINANCE_API_KEY", ""),
-		BinanceSecretKey: getEnv("BINANCE_SECRET_KEY", ""),
-		UseTestnet:       useTestnet,
-		DefaultSymbol:    getEnv("DEFAULT_SYMBOL", "BTCUSDT"),
-		DefaultSymbols:   defaultSymbols,
-		WSReconnectDelay: wsReconnect,
+		BinanceAPIKey:     getEnv("BINANCE_API_KEY", ""),
+		BinanceSecretKey:  getEnv("BINANCE_SECRET_KEY", ""),
+		UseTestnet:        useTestnet,
+		DefaultSymbol:     getEnv("DEFAULT_SYMBOL", "BTCUSDT"),
+		DefaultSymbols:    defaultSymbols,
+		WSReconnectDelay:  wsReconnect,
+		MaxCandles:        maxCandles,
+		UseFutures:        useFutures,
+		UseFuturesTestnet: useFuturesTestnet,
+		DefaultLeverage:   defaultLeverage,
+		DefaultIsolated:   defaultIsolated,
+		ExchangeName:      exchangeName,
 	}
 
 	return AppConfig
@@ -0,0 +1,242 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aphis/24hrt-backend/client"
+)
+
+// backtestQuoteAssets is checked longest-first so a symbol's quote asset is
+// identified correctly (e.g. "BUSD" before a coincidental shorter match).
+var backtestQuoteAssets = []string{"FDUSD", "TUSD", "USDT", "BUSD", "USDC", "BTC", "ETH", "BNB"}
+
+// splitSymbol best-effort splits a Binance-style symbol (e.g. "BTCUSDT") into
+// its base and quote assets, so a fill knows which simulated wallet balances
+// to debit/credit. Falls back to quoting against "USDT" if symbol doesn't end
+// in any known quote asset.
+func splitSymbol(symbol string) (base, quote string) {
+	for _, q := range backtestQuoteAssets {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return symbol[:len(symbol)-len(q)], q
+		}
+	}
+	return symbol, "USDT"
+}
+
+// BacktestExchange replays historical klines fetched once from a real
+// trading client instead of hitting the live market, so strategies can be
+// dry-run against past data. Each GetKlines call advances a per
+// symbol+interval cursor by one candle, simulating the backtest clock
+// ticking forward; market orders fill immediately at the most recently
+// replayed close price against a simulated wallet.
+type BacktestExchange struct {
+	source *client.TradingClient // only used to seed historical klines, never to place real orders
+
+	mu        sync.Mutex
+	history   map[string][]client.KlineData // keyed by symbol_interval
+	cursor    map[string]int                // keyed by symbol_interval
+	lastClose map[string]string             // keyed by symbol, last replayed close price
+
+	balance     map[string]float64
+	nextOrderID int64
+}
+
+// NewBacktestExchange seeds a simulated wallet (e.g. {"USDT": 10000}) and
+// replays history pulled from source on demand.
+func NewBacktestExchange(source *client.TradingClient, startingBalance map[string]float64) *BacktestExchange {
+	balance := make(map[string]float64, len(startingBalance))
+	for asset, amount := range startingBalance {
+		balance[asset] = amount
+	}
+
+	return &BacktestExchange{
+		source:    source,
+		history:   make(map[string][]client.KlineData),
+		cursor:    make(map[string]int),
+		lastClose: make(map[string]string),
+		balance:   balance,
+	}
+}
+
+func (b *BacktestExchange) Name() string { return "backtest" }
+
+func historyKey(symbol, interval string) string {
+	return symbol + "_" + interval
+}
+
+// GetKlines returns the historical window replayed so far, advancing the
+// cursor by one candle on every call. limit caps how much of the replayed
+// window is returned, same as the live adapters.
+func (b *BacktestExchange) GetKlines(symbol, interval, limit string) ([]client.KlineData, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := historyKey(symbol, interval)
+	klines, ok := b.history[key]
+	if !ok {
+		fetched, err := b.source.GetKlines(symbol, interval, limit)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: failed to seed history for %s %s: %w", symbol, interval, err)
+		}
+		klines = fetched
+		b.history[key] = klines
+	}
+
+	cursor := b.cursor[key]
+	if cursor < len(klines) {
+		cursor++
+		b.cursor[key] = cursor
+	}
+
+	window := klines[:cursor]
+	if n, err := strconv.Atoi(limit); err == nil && n > 0 && n < len(window) {
+		window = window[len(window)-n:]
+	}
+
+	if cursor > 0 {
+		b.lastClose[symbol] = klines[cursor-1].Close
+	}
+
+	return window, nil
+}
+
+// GetSymbolPrices reports the last replayed close price for every symbol
+// that's had at least one GetKlines call so far.
+func (b *BacktestExchange) GetSymbolPrices() ([]client.SymbolPrice, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prices := make([]client.SymbolPrice, 0, len(b.lastClose))
+	for symbol, price := range b.lastClose {
+		prices = append(prices, client.SymbolPrice{Symbol: symbol, Price: price})
+	}
+	return prices, nil
+}
+
+// PlaceMarketOrder fills immediately at the last replayed close price for
+// symbol, debiting/crediting the simulated wallet. It returns an error if no
+// candles have been replayed for symbol yet.
+func (b *BacktestExchange) PlaceMarketOrder(symbol, side, quantity string) (*client.OrderResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	price, ok := b.lastClose[symbol]
+	if !ok {
+		return nil, fmt.Errorf("backtest: no replayed price for %s yet, call GetKlines first", symbol)
+	}
+
+	b.applyFill(symbol, side, quantity, price)
+
+	b.nextOrderID++
+	return &client.OrderResult{
+		OrderID:     b.nextOrderID,
+		Symbol:      symbol,
+		Side:        side,
+		Type:        "MARKET",
+		Price:       price,
+		Quantity:    quantity,
+		Status:      "FILLED",
+		ExecutedQty: quantity,
+	}, nil
+}
+
+// PlaceLimitOrder fills immediately if the limit price is marketable against
+// the last replayed close (BUY at or above it, SELL at or below it),
+// mirroring how an exchange would cross a limit order against the current
+// price; otherwise it's returned as "NEW" and not tracked afterwards, so
+// later replayed candles never fill it.
+func (b *BacktestExchange) PlaceLimitOrder(symbol, side, quantity, price string) (*client.OrderResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	last, ok := b.lastClose[symbol]
+	if !ok {
+		return nil, fmt.Errorf("backtest: no replayed price for %s yet, call GetKlines first", symbol)
+	}
+
+	lastPrice, err := strconv.ParseFloat(last, 64)
+	if err != nil {
+		return nil, fmt.Errorf("backtest: invalid replayed price for %s: %w", symbol, err)
+	}
+	limitPrice, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return nil, fmt.Errorf("backtest: invalid limit price %q: %w", price, err)
+	}
+
+	marketable := (side == "BUY" && limitPrice >= lastPrice) || (side == "SELL" && limitPrice <= lastPrice)
+
+	b.nextOrderID++
+	result := &client.OrderResult{
+		OrderID:  b.nextOrderID,
+		Symbol:   symbol,
+		Side:     side,
+		Type:     "LIMIT",
+		Price:    price,
+		Quantity: quantity,
+		Status:   "NEW",
+	}
+	if marketable {
+		result.Status = "FILLED"
+		result.ExecutedQty = quantity
+		b.applyFill(symbol, side, quantity, price)
+	}
+	return result, nil
+}
+
+// CancelOrder always fails: PlaceMarketOrder and marketable PlaceLimitOrder
+// calls fill instantly and non-marketable limit orders aren't tracked past
+// the call that created them, so there's never an open order to cancel.
+func (b *BacktestExchange) CancelOrder(symbol string, orderID int64) error {
+	return fmt.Errorf("backtest: order %d not found, backtest orders fill immediately or are never tracked", orderID)
+}
+
+// TestConnectivity always succeeds: there's no remote venue to reach.
+func (b *BacktestExchange) TestConnectivity() error {
+	return nil
+}
+
+// GetAccountBalance returns the simulated wallet.
+func (b *BacktestExchange) GetAccountBalance() ([]client.BalanceInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	balances := make([]client.BalanceInfo, 0, len(b.balance))
+	for asset, amount := range b.balance {
+		balances = append(balances, client.BalanceInfo{
+			Asset: asset,
+			Free:  strconv.FormatFloat(amount, 'f', 8, 64),
+		})
+	}
+	return balances, nil
+}
+
+// GetOpenOrders always returns an empty list: market and marketable limit
+// orders fill the instant they're placed, and non-marketable limit orders
+// (returned as "NEW") aren't tracked, so they're never listed here.
+func (b *BacktestExchange) GetOpenOrders(symbol string) ([]*client.OrderResult, error) {
+	return nil, nil
+}
+
+// applyFill debits/credits the simulated wallet for a fill of quantity base
+// asset at price: a BUY spends quote to acquire base, a SELL spends base to
+// acquire quote. Callers must hold b.mu. Balances are allowed to go negative
+// instead of rejecting the fill — this is a dry-run P&L tool, not a margin
+// engine, so it never blocks a strategy on simulated insufficient funds.
+func (b *BacktestExchange) applyFill(symbol, side, quantity, price string) {
+	base, quote := splitSymbol(symbol)
+	qty, _ := strconv.ParseFloat(quantity, 64)
+	px, _ := strconv.ParseFloat(price, 64)
+	notional := qty * px
+
+	switch side {
+	case "BUY":
+		b.balance[base] += qty
+		b.balance[quote] -= notional
+	case "SELL":
+		b.balance[base] -= qty
+		b.balance[quote] += notional
+	}
+}
@@ -0,0 +1,60 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/aphis/24hrt-backend/client"
+)
+
+// BinanceExchange adapts an existing *client.TradingClient to the Exchange
+// interface. It's a thin pass-through since TradingClient already speaks
+// Binance's REST API directly.
+type BinanceExchange struct {
+	tc *client.TradingClient
+}
+
+// NewBinanceExchange wraps an already-constructed trading client.
+func NewBinanceExchange(tc *client.TradingClient) *BinanceExchange {
+	return &BinanceExchange{tc: tc}
+}
+
+func (b *BinanceExchange) Name() string { return "binance" }
+
+func (b *BinanceExchange) GetKlines(symbol, interval, limit string) ([]client.KlineData, error) {
+	return b.tc.GetKlines(symbol, interval, limit)
+}
+
+func (b *BinanceExchange) GetSymbolPrices() ([]client.SymbolPrice, error) {
+	return b.tc.GetSymbolPrices()
+}
+
+func (b *BinanceExchange) PlaceMarketOrder(symbol, side, quantity string) (*client.OrderResult, error) {
+	return b.tc.PlaceMarketOrder(symbol, side, quantity)
+}
+
+func (b *BinanceExchange) PlaceLimitOrder(symbol, side, quantity, price string) (*client.OrderResult, error) {
+	switch side {
+	case "BUY":
+		return b.tc.PlaceLimitBuyOrder(symbol, quantity, price)
+	case "SELL":
+		return b.tc.PlaceLimitSellOrder(symbol, quantity, price)
+	default:
+		return nil, fmt.Errorf("invalid order side: %s", side)
+	}
+}
+
+func (b *BinanceExchange) CancelOrder(symbol string, orderID int64) error {
+	return b.tc.CancelOrder(symbol, orderID)
+}
+
+func (b *BinanceExchange) GetAccountBalance() ([]client.BalanceInfo, error) {
+	return b.tc.GetAccountBalance()
+}
+
+func (b *BinanceExchange) GetOpenOrders(symbol string) ([]*client.OrderResult, error) {
+	return b.tc.GetOpenOrders(symbol)
+}
+
+func (b *BinanceExchange) TestConnectivity() error {
+	return b.tc.TestConnectivity()
+}
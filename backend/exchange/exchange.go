@@ -0,0 +1,31 @@
+// Package exchange defines a venue-agnostic trading interface so the server
+// can serve more than one exchange session concurrently, each picked per
+// request via ?exchange=<name>. Binance is the only fully wired adapter
+// today; Backtest replays historical klines for dry-running strategies, and
+// the FTX/KuCoin-style adapters are stubs showing where a real integration
+// would plug in.
+package exchange
+
+import "github.com/aphis/24hrt-backend/client"
+
+// Exchange is the common surface every venue adapter implements. Streaming
+// (trades/klines/depth) is intentionally not part of this interface: the
+// server's shared PriceStreamer/KlineStreamer/BookStreamer already own that
+// lifecycle per symbol+interval, so adapters only need to cover the
+// request/response REST surface used by the HTTP handlers.
+type Exchange interface {
+	// Name identifies the adapter, matching the ?exchange= query value used
+	// to select it from a Registry.
+	Name() string
+
+	GetKlines(symbol, interval, limit string) ([]client.KlineData, error)
+	GetSymbolPrices() ([]client.SymbolPrice, error)
+	PlaceMarketOrder(symbol, side, quantity string) (*client.OrderResult, error)
+	PlaceLimitOrder(symbol, side, quantity, price string) (*client.OrderResult, error)
+	CancelOrder(symbol string, orderID int64) error
+	GetAccountBalance() ([]client.BalanceInfo, error)
+	GetOpenOrders(symbol string) ([]*client.OrderResult, error)
+	// TestConnectivity reports whether the venue is reachable, independent of
+	// any symbol/account-specific call.
+	TestConnectivity() error
+}
@@ -0,0 +1,58 @@
+package exchange
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultExchange is the registry default NewRegistry falls back to when it's
+// given an empty default name, keeping existing single-venue behavior
+// unchanged.
+const DefaultExchange = "binance"
+
+// Registry holds one Exchange session per venue name, letting the server
+// serve several exchanges concurrently and pick one per request.
+type Registry struct {
+	mu              sync.RWMutex
+	exchanges       map[string]Exchange
+	defaultExchange string
+}
+
+// NewRegistry creates an empty registry; callers Register each adapter they
+// want reachable via ?exchange=. defaultExchange is used when a request's
+// ?exchange= is empty (typically cfg.ExchangeName); an empty defaultExchange
+// falls back to DefaultExchange.
+func NewRegistry(defaultExchange string) *Registry {
+	if defaultExchange == "" {
+		defaultExchange = DefaultExchange
+	}
+	return &Registry{
+		exchanges:       make(map[string]Exchange),
+		defaultExchange: defaultExchange,
+	}
+}
+
+// Register adds or replaces the adapter for ex.Name().
+func (r *Registry) Register(ex Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges[ex.Name()] = ex
+}
+
+// Get resolves a venue by name, falling back to the registry's configured
+// default (see NewRegistry) when name is empty so existing callers that
+// don't pass ?exchange= keep working.
+func (r *Registry) Get(name string) (Exchange, error) {
+	if name == "" {
+		name = r.defaultExchange
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ex, ok := r.exchanges[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q", name)
+	}
+	return ex, nil
+}
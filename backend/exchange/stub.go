@@ -0,0 +1,68 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/aphis/24hrt-backend/client"
+)
+
+// stubExchange is the shared implementation behind the FTX-style and
+// KuCoin-style placeholders: they satisfy the Exchange interface and can be
+// registered today, but every call fails fast until a real API client is
+// wired in behind name.
+type stubExchange struct {
+	name string
+}
+
+func (s *stubExchange) Name() string { return s.name }
+
+func (s *stubExchange) notImplemented(op string) error {
+	return fmt.Errorf("%s: %s not implemented, no live API client configured for this venue", s.name, op)
+}
+
+func (s *stubExchange) GetKlines(symbol, interval, limit string) ([]client.KlineData, error) {
+	return nil, s.notImplemented("GetKlines")
+}
+
+func (s *stubExchange) GetSymbolPrices() ([]client.SymbolPrice, error) {
+	return nil, s.notImplemented("GetSymbolPrices")
+}
+
+func (s *stubExchange) PlaceMarketOrder(symbol, side, quantity string) (*client.OrderResult, error) {
+	return nil, s.notImplemented("PlaceMarketOrder")
+}
+
+func (s *stubExchange) PlaceLimitOrder(symbol, side, quantity, price string) (*client.OrderResult, error) {
+	return nil, s.notImplemented("PlaceLimitOrder")
+}
+
+func (s *stubExchange) CancelOrder(symbol string, orderID int64) error {
+	return s.notImplemented("CancelOrder")
+}
+
+func (s *stubExchange) GetAccountBalance() ([]client.BalanceInfo, error) {
+	return nil, s.notImplemented("GetAccountBalance")
+}
+
+func (s *stubExchange) GetOpenOrders(symbol string) ([]*client.OrderResult, error) {
+	return nil, s.notImplemented("GetOpenOrders")
+}
+
+func (s *stubExchange) TestConnectivity() error {
+	return s.notImplemented("TestConnectivity")
+}
+
+// NewFTXExchange returns a placeholder FTX-style adapter. FTX's REST/WS
+// shapes differ enough from Binance (e.g. markets use "BTC/USDT" instead of
+// "BTCUSDT") that this intentionally isn't a real integration yet — it only
+// exists so the Registry and ?exchange= wiring have a second adapter to
+// dispatch to.
+func NewFTXExchange() Exchange {
+	return &stubExchange{name: "ftx"}
+}
+
+// NewKuCoinExchange returns a placeholder KuCoin-style adapter, same caveat
+// as NewFTXExchange.
+func NewKuCoinExchange() Exchange {
+	return &stubExchange{name: "kucoin"}
+}
@@ -1,27 +1,37 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/aphis/24hrt-backend/client"
 	"github.com/aphis/24hrt-backend/config"
 	"github.com/aphis/24hrt-backend/server"
-	"github.com/aphis/24hrt-backend/websocket"
 )
 
+// shutdownTimeout bounds how long we wait for in-flight requests and
+// subsystem goroutines to wind down once an interrupt is received.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	chaos := flag.Bool("chaos", false, "periodically kill upstream sockets and drop random client connections, to exercise the reconnect path in CI integration tests")
+	flag.Parse()
+
 	// Set log output to stdout instead of stderr
 	log.SetOutput(os.Stdout)
-	
+
 	log.Println("🚀 Starting 24HrT Trading Bot Backend...")
 
 	// Load configuration
 	cfg := config.Load()
-	
+
 	// Validate API keys
 	if cfg.BinanceAPIKey == "" || cfg.BinanceAPIKey == "your_testnet_api_key_here" {
 		log.Println("⚠️  Warning: No valid API key found!")
@@ -29,8 +39,14 @@ func main() {
 		log.Println("📝 Then create a .env file with your keys")
 	}
 
+	// ctx is the root context for every subsystem the bot owns (trading
+	// client REST calls, the shared streamers, the HTTP server). Cancelling
+	// it on SIGINT/SIGTERM is how graceful shutdown propagates everywhere.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Create trading client
-	tradingClient := client.NewTradingClient(cfg)
+	tradingClient := client.NewTradingClient(ctx, cfg)
 
 	// Test connectivity
 	if err := tradingClient.TestConnectivity(); err != nil {
@@ -51,53 +67,59 @@ func main() {
 		}
 	}
 
-	// Create HTTP server for Electron communication with trading client
-	httpServer := server.NewServer(tradingClient)
-
-	// Start HTTP server in a goroutine
-	go func() {
-		if err := httpServer.Start("8080"); err != nil {
-			log.Printf("❌ HTTP server error: %v", err)
+	// Futures trading is opt-in; it shares tradingClient's time sync but
+	// otherwise trades against a separate leveraged account. futuresClient
+	// stays nil when disabled; the server's /api/futures/* handlers check
+	// for that and report futures as unavailable rather than panicking.
+	var futuresClient *client.FuturesTradingClient
+	if cfg.UseFutures {
+		futuresClient = client.NewFuturesTradingClient(ctx, cfg, tradingClient)
+		if err := futuresClient.SetLeverage(cfg.DefaultSymbol, cfg.DefaultLeverage); err != nil {
+			log.Printf("⚠️  Could not set default futures leverage: %v", err)
+		}
+		if err := futuresClient.SetMarginType(cfg.DefaultSymbol, cfg.DefaultIsolated); err != nil {
+			log.Printf("⚠️  Could not set default futures margin type: %v", err)
 		}
-	}()
-
-	// Start WebSocket price stream
-	priceStreamer := websocket.NewPriceStreamer(cfg.DefaultSymbol)
-	if err := priceStreamer.Start(); err != nil {
-		log.Printf("❌ Failed to start price stream: %v", err)
-		return
 	}
-	defer priceStreamer.Stop()
 
-	// Handle price updates and send to HTTP server
-	go func() {
-		for update := range priceStreamer.GetUpdateChannel() {
-			// Send price to all connected WebSocket clients (Electron)
-			httpServer.SendPrice(update.Symbol, update.Price, update.Timestamp)
-			
-			// Log occasionally for debugging (uncomment if needed)
-			// log.Printf("💰 Price Update: %s = %s", update.Symbol, update.Price)
-		}
-	}()
+	// Create HTTP server for Electron communication with trading client.
+	// The server owns the shared combined-stream price streamer covering
+	// cfg.DefaultSymbols and exposes /api/subscribe, /api/unsubscribe so the
+	// Electron frontend can grow or shrink the watched set at runtime.
+	httpServer := server.NewServer(ctx, tradingClient, futuresClient)
+
+	if *chaos {
+		httpServer.EnableChaos(ctx)
+	}
 
-	// Handle WebSocket errors
+	var wg sync.WaitGroup
+	wg.Add(1)
 	go func() {
-		for err := range priceStreamer.GetErrorChannel() {
-			log.Printf("⚠️  Stream error: %v", err)
+		defer wg.Done()
+		if err := httpServer.Start("8080"); err != nil {
+			log.Printf("❌ HTTP server error: %v", err)
 		}
 	}()
 
 	log.Println("✅ Backend is running!")
-	log.Printf("📊 Watching %s price updates...", cfg.DefaultSymbol)
+	log.Printf("📊 Watching %d default symbol(s): %s", len(cfg.DefaultSymbols), strings.Join(cfg.DefaultSymbols, ", "))
 	log.Println("🌐 HTTP Server: http://localhost:8080")
 	log.Println("🔌 WebSocket: ws://localhost:8080/api/price")
 	log.Println("Press Ctrl+C to stop")
 
 	// Wait for interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
-
+	<-ctx.Done()
 	log.Println("\n👋 Shutting down gracefully...")
-	time.Sleep(500 * time.Millisecond)
+
+	// Give the HTTP server a bounded window to drain in-flight requests, then
+	// stop every streamer (price/book/user/kline) so in-flight orders either
+	// finish or surface their error before we exit.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️  HTTP server shutdown error: %v", err)
+	}
+	httpServer.Cleanup()
+
+	wg.Wait()
 }
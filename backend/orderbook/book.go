@@ -0,0 +1,290 @@
+// Package orderbook maintains local L2 order books for Binance symbols,
+// following the exchange's documented depth-stream synchronization
+// algorithm: seed from a REST snapshot, buffer live diff events, discard
+// anything older than the snapshot, then apply the remainder in order and
+// resync whenever a gap is detected.
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// Level is a single price/quantity entry on one side of the book.
+type Level struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// DiffEvent is a single Binance `@depth` diff payload.
+type DiffEvent struct {
+	FirstUpdateID int64      // U
+	FinalUpdateID int64      // u
+	Bids          [][]string // [price, quantity]
+	Asks          [][]string
+}
+
+// Book is the live local order book for one symbol.
+type Book struct {
+	symbol string
+
+	mu           sync.RWMutex
+	ready        bool
+	lastUpdateID int64
+	bids         map[float64]float64
+	asks         map[float64]float64
+	buffered     []DiffEvent
+
+	restClient *binance.Client
+
+	// hooksMu guards onReady/onPush separately from mu, so they can be fired
+	// from inside a method that already holds mu without deadlocking.
+	hooksMu sync.Mutex
+	onReady []func(symbol string)
+	onPush  []func(symbol string, event DiffEvent)
+}
+
+func newBook(symbol string) *Book {
+	return &Book{
+		symbol:     symbol,
+		bids:       make(map[float64]float64),
+		asks:       make(map[float64]float64),
+		restClient: binance.NewClient("", ""),
+	}
+}
+
+// OnReady registers fn to be called (synchronously, on whatever goroutine
+// triggered the transition) every time the book becomes ready — the first
+// time it syncs, and again after every resync. Mirrors bbgo's DepthFrame
+// OnReady hook.
+func (b *Book) OnReady(fn func(symbol string)) {
+	b.hooksMu.Lock()
+	b.onReady = append(b.onReady, fn)
+	b.hooksMu.Unlock()
+}
+
+// OnPush registers fn to be called with every diff event actually applied to
+// the book (i.e. after sync, never for buffered-but-not-yet-applied events).
+// Mirrors bbgo's DepthFrame OnPush hook.
+func (b *Book) OnPush(fn func(symbol string, event DiffEvent)) {
+	b.hooksMu.Lock()
+	b.onPush = append(b.onPush, fn)
+	b.hooksMu.Unlock()
+}
+
+func (b *Book) fireOnReady() {
+	b.hooksMu.Lock()
+	hooks := append([]func(string){}, b.onReady...)
+	b.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(b.symbol)
+	}
+}
+
+func (b *Book) firePush(event DiffEvent) {
+	b.hooksMu.Lock()
+	hooks := append([]func(string, DiffEvent){}, b.onPush...)
+	b.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(b.symbol, event)
+	}
+}
+
+// ApplyDiff feeds a live diff event into the book, buffering it until the
+// snapshot has been fetched and applying it in order afterwards. Any gap
+// (U > lastUpdateID+1 after the first applied event) triggers a full resync.
+func (b *Book) ApplyDiff(event DiffEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.ready {
+		b.buffered = append(b.buffered, event)
+		return
+	}
+
+	if event.FinalUpdateID <= b.lastUpdateID {
+		// Already applied (or older than our current snapshot); ignore.
+		return
+	}
+
+	if event.FirstUpdateID > b.lastUpdateID+1 {
+		log.Printf("⚠️  Gap detected in %s order book (have %d, got U=%d) — resyncing", b.symbol, b.lastUpdateID, event.FirstUpdateID)
+		b.ready = false
+		b.buffered = []DiffEvent{event}
+		go b.resync()
+		return
+	}
+
+	b.applyLocked(event)
+}
+
+// resync fetches a fresh REST snapshot and replays any buffered diffs on top
+// of it, per Binance's documented algorithm.
+func (b *Book) resync() {
+	snapshot, err := b.restClient.NewDepthService().Symbol(b.symbol).Limit(1000).Do(context.Background())
+	if err != nil {
+		log.Printf("❌ Failed to fetch depth snapshot for %s: %v", b.symbol, err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids = make(map[float64]float64)
+	b.asks = make(map[float64]float64)
+	for _, bid := range snapshot.Bids {
+		setLevel(b.bids, bid.Price, bid.Quantity)
+	}
+	for _, ask := range snapshot.Asks {
+		setLevel(b.asks, ask.Price, ask.Quantity)
+	}
+	b.lastUpdateID = snapshot.LastUpdateID
+
+	// Drop buffered events at or before the snapshot, then require the first
+	// surviving event to straddle the snapshot's lastUpdateId.
+	var pending []DiffEvent
+	for _, event := range b.buffered {
+		if event.FinalUpdateID <= b.lastUpdateID {
+			continue
+		}
+		pending = append(pending, event)
+	}
+	b.buffered = nil
+
+	if len(pending) > 0 {
+		first := pending[0]
+		if first.FirstUpdateID > b.lastUpdateID+1 {
+			log.Printf("⚠️  Snapshot for %s still doesn't cover the buffered events — will retry on next diff", b.symbol)
+			return
+		}
+	}
+
+	b.ready = true
+	for _, event := range pending {
+		b.applyLocked(event)
+	}
+
+	log.Printf("📗 Resynced %s order book at lastUpdateId=%d", b.symbol, b.lastUpdateID)
+	b.fireOnReady()
+}
+
+// applyLocked applies a single diff event; caller must hold b.mu.
+func (b *Book) applyLocked(event DiffEvent) {
+	for _, bid := range event.Bids {
+		setLevel(b.bids, bid[0], bid[1])
+	}
+	for _, ask := range event.Asks {
+		setLevel(b.asks, ask[0], ask[1])
+	}
+	b.lastUpdateID = event.FinalUpdateID
+
+	b.firePush(event)
+}
+
+// setLevel updates a price level, removing it when the quantity is zero.
+func setLevel(side map[float64]float64, priceStr, qtyStr string) {
+	price, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil {
+		return
+	}
+	qty, err := strconv.ParseFloat(qtyStr, 64)
+	if err != nil {
+		return
+	}
+
+	if qty == 0 {
+		delete(side, price)
+		return
+	}
+	side[price] = qty
+}
+
+// SeedFromSnapshot primes the book directly from a REST snapshot that it
+// fetches itself, synchronously. Callers that need the book right away (e.g.
+// Manager.GetBook on first access) can use this instead of waiting for a
+// resync() triggered by the diff stream.
+func (b *Book) SeedFromSnapshot() error {
+	snapshot, err := b.restClient.NewDepthService().Symbol(b.symbol).Limit(1000).Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to fetch depth snapshot for %s: %w", b.symbol, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids = make(map[float64]float64)
+	b.asks = make(map[float64]float64)
+	for _, bid := range snapshot.Bids {
+		setLevel(b.bids, bid.Price, bid.Quantity)
+	}
+	for _, ask := range snapshot.Asks {
+		setLevel(b.asks, ask.Price, ask.Quantity)
+	}
+	b.lastUpdateID = snapshot.LastUpdateID
+
+	// Drop buffered events at or before the snapshot, then require the first
+	// surviving event to straddle the snapshot's lastUpdateId, same as
+	// resync() — without this check a gap between the snapshot and the
+	// first buffered event would silently corrupt the book instead of
+	// triggering a resync.
+	var pending []DiffEvent
+	for _, event := range b.buffered {
+		if event.FinalUpdateID <= b.lastUpdateID {
+			continue
+		}
+		pending = append(pending, event)
+	}
+	b.buffered = pending
+
+	if len(pending) > 0 {
+		first := pending[0]
+		if first.FirstUpdateID > b.lastUpdateID+1 {
+			go b.resync()
+			return fmt.Errorf("snapshot for %s doesn't cover the buffered events, resyncing", b.symbol)
+		}
+	}
+
+	b.ready = true
+	for _, event := range pending {
+		b.applyLocked(event)
+	}
+	b.buffered = nil
+
+	b.fireOnReady()
+	return nil
+}
+
+// Snapshot returns the current bid/ask levels, best (highest) bid first and
+// best (lowest) ask first.
+func (b *Book) Snapshot() (bids, asks []Level) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bids = sortedLevels(b.bids, true)
+	asks = sortedLevels(b.asks, false)
+	return bids, asks
+}
+
+func sortedLevels(side map[float64]float64, descending bool) []Level {
+	levels := make([]Level, 0, len(side))
+	for price, qty := range side {
+		levels = append(levels, Level{Price: price, Quantity: qty})
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price > levels[j].Price
+		}
+		return levels[i].Price < levels[j].Price
+	})
+
+	return levels
+}
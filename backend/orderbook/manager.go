@@ -0,0 +1,98 @@
+package orderbook
+
+import (
+	"sync"
+)
+
+// Manager owns one Book per symbol and lazily seeds new ones on first use.
+type Manager struct {
+	mu    sync.Mutex
+	books map[string]*Book
+
+	onReadyHooks []func(symbol string)
+	onPushHooks  []func(symbol string, event DiffEvent)
+}
+
+// NewManager creates an empty order book manager.
+func NewManager() *Manager {
+	return &Manager{books: make(map[string]*Book)}
+}
+
+// bookFor returns (creating if necessary) the Book for a symbol, wiring up
+// any hooks registered via Manager.OnReady/OnPush before handing it back.
+func (m *Manager) bookFor(symbol string) *Book {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	book, ok := m.books[symbol]
+	if !ok {
+		book = newBook(symbol)
+		for _, hook := range m.onReadyHooks {
+			book.OnReady(hook)
+		}
+		for _, hook := range m.onPushHooks {
+			book.OnPush(hook)
+		}
+		m.books[symbol] = book
+	}
+	return book
+}
+
+// OnReady registers fn on every book the manager owns (including ones
+// created later), called each time that symbol's book becomes ready.
+func (m *Manager) OnReady(fn func(symbol string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onReadyHooks = append(m.onReadyHooks, fn)
+	for _, book := range m.books {
+		book.OnReady(fn)
+	}
+}
+
+// OnPush registers fn on every book the manager owns (including ones
+// created later), called with every diff event applied to that symbol's book.
+func (m *Manager) OnPush(fn func(symbol string, event DiffEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onPushHooks = append(m.onPushHooks, fn)
+	for _, book := range m.books {
+		book.OnPush(fn)
+	}
+}
+
+// ApplyDiff routes a live diff event to the relevant symbol's book, seeding a
+// snapshot for it first if this is the first time the symbol has been seen.
+func (m *Manager) ApplyDiff(symbol string, event DiffEvent) {
+	book := m.bookFor(symbol)
+
+	book.mu.RLock()
+	seeded := book.ready || len(book.buffered) > 0
+	book.mu.RUnlock()
+
+	if !seeded {
+		go book.resync()
+	}
+
+	book.ApplyDiff(event)
+}
+
+// GetBook returns the current bid/ask levels for a symbol, seeding a fresh
+// REST snapshot synchronously if this is the first request for it.
+func (m *Manager) GetBook(symbol string) (bids, asks []Level, err error) {
+	book := m.bookFor(symbol)
+
+	book.mu.RLock()
+	ready := book.ready
+	book.mu.RUnlock()
+
+	if !ready {
+		if err := book.SeedFromSnapshot(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	bids, asks = book.Snapshot()
+	return bids, asks, nil
+}
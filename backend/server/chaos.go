@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/aphis/24hrt-backend/websocket"
+)
+
+// chaosInterval is how often the chaos monkey strikes when enabled.
+const chaosInterval = 30 * time.Second
+
+// EnableChaos starts a background goroutine that periodically kills the
+// shared price and ticker streamers' upstream connections plus one randomly
+// chosen kline streamer's, and drops a random connected client,
+// so the reconnect/catch-up path gets exercised continuously under CI
+// integration tests (the "flappyws" pattern). Only ever wired up behind the
+// --chaos flag; never enable it in production.
+func (s *Server) EnableChaos(ctx context.Context) {
+	log.Printf("☠️  Chaos mode enabled: striking every %s", chaosInterval)
+	go s.chaosLoop(ctx)
+}
+
+func (s *Server) chaosLoop(ctx context.Context) {
+	ticker := time.NewTicker(chaosInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.chaosStrike()
+		}
+	}
+}
+
+func (s *Server) chaosStrike() {
+	if s.priceStreamer != nil {
+		s.priceStreamer.ChaosKill()
+	}
+
+	if s.tickerStreamer != nil {
+		s.tickerStreamer.ChaosKill()
+	}
+
+	s.streamersMux.Lock()
+	klineStreamers := make([]*websocket.KlineStreamer, 0, len(s.klineStreamers))
+	for _, ks := range s.klineStreamers {
+		klineStreamers = append(klineStreamers, ks)
+	}
+	s.streamersMux.Unlock()
+
+	if len(klineStreamers) > 0 {
+		klineStreamers[rand.Intn(len(klineStreamers))].ChaosKill()
+	}
+
+	s.broadcastHub.DropRandomClient()
+
+	log.Println("☠️  Chaos strike: killed upstream sockets and dropped a random client")
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,8 +9,12 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/adshao/go-binance/v2/futures"
 	"github.com/aphis/24hrt-backend/client"
 	"github.com/aphis/24hrt-backend/config"
+	"github.com/aphis/24hrt-backend/exchange"
+	"github.com/aphis/24hrt-backend/orderbook"
+	"github.com/aphis/24hrt-backend/userstream"
 	"github.com/aphis/24hrt-backend/websocket"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -23,6 +28,8 @@ type ClientInfo struct {
 }
 
 type Server struct {
+	ctx            context.Context
+	httpServer     *http.Server
 	router         *gin.Engine
 	upgrader       ws.Upgrader
 	priceClients   map[*ws.Conn]*ClientInfo
@@ -32,7 +39,28 @@ type Server struct {
 	klineStreamers map[string]*websocket.KlineStreamer
 	streamersMux   sync.Mutex
 	tradingClient  *client.TradingClient
-	broadcastHub   *websocket.BroadcastHub // Add enhanced broadcasting
+	broadcastHub   *websocket.BroadcastHub      // Add enhanced broadcasting
+	priceStreamer  *websocket.PriceStreamer     // Shared combined-stream watching config.AppConfig.DefaultSymbols
+	bookStreamer   *websocket.BookStreamer      // Feeds depth diffs into orderBooks
+	multiStreamer  *websocket.MultiStreamer     // Dynamic per-symbol/interval kline subscriptions, driven by ExchangeStream
+	orderBooks     *orderbook.Manager           // Local L2 books, keyed by symbol
+	userStreamer   *userstream.UserStreamer     // Account/order/balance push updates
+	exchanges      *exchange.Registry           // Venue sessions selectable per request via ?exchange=
+	tickerStreamer *websocket.TickerStreamer    // All-market 24hr ticker cache, fed by !ticker@arr
+	futuresClient  *client.FuturesTradingClient // Opt-in (cfg.UseFutures); nil when futures trading is disabled
+}
+
+// userStreamTopic is the pseudo-symbol used to register /api/user clients
+// with broadcastHub, since account events aren't scoped to one trading pair.
+const userStreamTopic = "_USER"
+
+// tickerAllTopic is the pseudo-symbol /api/ticker/stream clients register
+// under when they don't filter to a single symbol, so a market overview grid
+// can receive every ticker update on one connection.
+const tickerAllTopic = "_TICKER_ALL"
+
+type SubscriptionRequest struct {
+	Symbol string `json:"symbol" binding:"required"`
 }
 
 type PriceMessage struct {
@@ -55,40 +83,243 @@ type BalanceResponse struct {
 	Locked string `json:"locked"`
 }
 
-// NewServer creates a new HTTP server instance
-func NewServer(tradingClient *client.TradingClient) *Server {
+type FuturesOrderRequest struct {
+	Symbol   string `json:"symbol" binding:"required"`
+	Side     string `json:"side" binding:"required"`
+	Quantity string `json:"quantity" binding:"required"`
+	Type     string `json:"type"`
+	Price    string `json:"price,omitempty"`
+	// PositionSide is only meaningful in hedge mode; defaults to "BOTH"
+	// (one-way mode), matching futures.PositionSideTypeBoth.
+	PositionSide string `json:"positionSide,omitempty"`
+}
+
+type FuturesMarginTypeRequest struct {
+	Symbol   string `json:"symbol" binding:"required"`
+	Isolated bool   `json:"isolated"`
+}
+
+type FuturesPositionModeRequest struct {
+	Hedge bool `json:"hedge"`
+}
+
+type FuturesLeverageRequest struct {
+	Symbol   string `json:"symbol" binding:"required"`
+	Leverage int    `json:"leverage" binding:"required"`
+}
+
+// NewServer creates a new HTTP server instance. ctx is passed to the shared
+// price, multi-kline and ticker streamers, so cancelling it stops them; the
+// book and user streamers don't take it and are stopped by Cleanup. Shutdown
+// takes its own context to bound how long it waits for in-flight requests
+// to finish. futuresClient is nil when
+// cfg.UseFutures is false; the /api/futures/* handlers report futures as
+// unavailable in that case instead of touching a nil client.
+func NewServer(ctx context.Context, tradingClient *client.TradingClient, futuresClient *client.FuturesTradingClient) *Server {
 	gin.SetMode(gin.ReleaseMode)
 
 	s := &Server{
+		ctx:            ctx,
 		router:         gin.Default(),
 		priceClients:   make(map[*ws.Conn]*ClientInfo),
 		klineClients:   make(map[*ws.Conn]*ClientInfo),
 		streamers:      make(map[string]*websocket.PriceStreamer),
 		klineStreamers: make(map[string]*websocket.KlineStreamer),
 		tradingClient:  tradingClient,
+		futuresClient:  futuresClient,
 		broadcastHub:   websocket.NewBroadcastHub(), // Initialize broadcast hub
+		orderBooks:     orderbook.NewManager(),
+		exchanges:      exchange.NewRegistry(config.AppConfig.ExchangeName),
 		upgrader: ws.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true
 			},
+			// Negotiates permessage-deflate with clients that request it;
+			// actually compressing writes is opt-in per connection (see
+			// websocket.RegisterClientWithOptions), since plain JSON clients
+			// don't need the CPU cost.
+			EnableCompression: true,
 		},
 	}
 
+	// Log every order book sync/resync so a CI chaos run or a flaky feed
+	// shows up in the logs instead of only as a silently-stale snapshot.
+	s.orderBooks.OnReady(func(symbol string) {
+		log.Printf("📗 %s order book synced", symbol)
+	})
+
 	// Setup CORS
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
-	s.router.Use(cors.New(config))
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	s.router.Use(cors.New(corsConfig))
+
+	// Register venue sessions selectable per request via ?exchange=. Binance
+	// is backed by the live tradingClient; backtest replays its historical
+	// klines against a simulated wallet; ftx/kucoin are placeholders until a
+	// real API client exists for them.
+	s.exchanges.Register(exchange.NewBinanceExchange(tradingClient))
+	s.exchanges.Register(exchange.NewBacktestExchange(tradingClient, map[string]float64{"USDT": 10000}))
+	s.exchanges.Register(exchange.NewFTXExchange())
+	s.exchanges.Register(exchange.NewKuCoinExchange())
 
 	s.setupRoutes()
-	
+
 	// Start broadcast hub
 	go s.broadcastHub.Run()
-	
+
+	// Start the shared combined-stream price streamer covering every default
+	// symbol. Clients connected via /api/price receive updates for whatever
+	// symbols they're subscribed to; /api/subscribe and /api/unsubscribe let
+	// the Electron frontend grow or shrink the watched set at runtime.
+	s.priceStreamer = websocket.NewPriceStreamer(ctx, config.AppConfig.DefaultSymbols, config.AppConfig.WSReconnectDelay)
+	if err := s.priceStreamer.Start(); err != nil {
+		log.Printf("❌ Failed to start shared price streamer: %v", err)
+	} else {
+		go s.forwardPriceUpdates()
+	}
+
+	// Start the shared depth-diff streamer and feed it into the local order
+	// book manager so /api/book/:symbol and the "depth:<symbol>" /api/stream
+	// topic can serve live L2 depth instead of just last-trade prices.
+	s.bookStreamer = websocket.NewBookStreamer(config.AppConfig.DefaultSymbols)
+	if err := s.bookStreamer.Start(); err != nil {
+		log.Printf("❌ Failed to start shared depth streamer: %v", err)
+	} else {
+		go s.forwardBookUpdates()
+	}
+
+	// Start the dynamic kline streamer backing BinanceStream's Subscribe, and
+	// plug it (plus the price/book streamers above) into the hub's
+	// ExchangeStream registry by venue name. /api/stream subscriptions are
+	// served by the server's own streamers and don't look up this registry.
+	s.multiStreamer = websocket.NewMultiStreamer(ctx, config.AppConfig.WSReconnectDelay, config.AppConfig.MaxCandles, tradingClient)
+	if err := s.multiStreamer.Start(); err != nil {
+		log.Printf("❌ Failed to start shared kline streamer: %v", err)
+	}
+
+	binanceStream := websocket.NewBinanceStream(s.multiStreamer, s.priceStreamer, s.bookStreamer)
+	if err := binanceStream.Connect(ctx); err != nil {
+		log.Printf("❌ Failed to connect Binance ExchangeStream adapter: %v", err)
+	}
+	s.broadcastHub.RegisterStream("binance", binanceStream)
+	s.broadcastHub.RegisterStream("ftx", websocket.NewFTXStream())
+	s.broadcastHub.RegisterStream("kucoin", websocket.NewKuCoinStream())
+
+	// Start the user data stream for account/order/balance push updates, if
+	// API keys are configured. Without valid keys CreateListenKey fails fast
+	// and we simply skip it rather than polling.
+	s.userStreamer = userstream.NewUserStreamer(tradingClient, tradingClient.IsTestnet())
+	if err := s.userStreamer.Start(); err != nil {
+		log.Printf("⚠️  User data stream not started: %v", err)
+	} else {
+		go s.forwardUserStreamUpdates()
+	}
+
+	// Start the all-market 24hr ticker streamer so /api/ticker/24hr serves
+	// from an in-memory cache instead of synthesizing zeros per request.
+	s.tickerStreamer = websocket.NewTickerStreamer(ctx)
+	if err := s.tickerStreamer.Start(); err != nil {
+		log.Printf("❌ Failed to start ticker streamer: %v", err)
+	} else {
+		go s.forwardTickerUpdates()
+	}
+
 	return s
 }
 
+// forwardTickerUpdates relays every 24hr ticker update both to its own
+// symbol's topic and to tickerAllTopic, so /api/ticker/stream clients can
+// subscribe to one symbol or the whole market, and likewise to the
+// "ticker:<symbol>" and bare "ticker" /api/stream topics.
+func (s *Server) forwardTickerUpdates() {
+	for ticker := range s.tickerStreamer.GetUpdateChannel() {
+		frame := map[string]interface{}{
+			"type": "ticker",
+			"data": ticker,
+		}
+		s.broadcastHub.BroadcastToSymbol(ticker.Symbol, frame)
+		s.broadcastHub.BroadcastToSymbol(tickerAllTopic, frame)
+		s.broadcastHub.BroadcastToTopic("ticker:"+ticker.Symbol, frame)
+		s.broadcastHub.BroadcastToTopic("ticker", frame)
+	}
+}
+
+// forwardUserStreamUpdates relays order and balance events from the user
+// data stream to every client connected to /api/user.
+func (s *Server) forwardUserStreamUpdates() {
+	go func() {
+		for update := range s.userStreamer.GetOrderUpdateChannel() {
+			s.broadcastHub.BroadcastToSymbol(userStreamTopic, map[string]interface{}{
+				"type": "order",
+				"data": update,
+			})
+		}
+	}()
+
+	for update := range s.userStreamer.GetBalanceUpdateChannel() {
+		s.broadcastHub.BroadcastToSymbol(userStreamTopic, map[string]interface{}{
+			"type": "balance",
+			"data": update,
+		})
+	}
+}
+
+// forwardBookUpdates applies live depth diffs to the order book manager and
+// broadcasts the resulting full book (every bid/ask level) to clients
+// registered on that symbol and to the "depth:<symbol>" topic.
+func (s *Server) forwardBookUpdates() {
+	for update := range s.bookStreamer.GetUpdateChannel() {
+		s.orderBooks.ApplyDiff(update.Symbol, orderbook.DiffEvent{
+			FirstUpdateID: update.FirstUpdateID,
+			FinalUpdateID: update.FinalUpdateID,
+			Bids:          update.Bids,
+			Asks:          update.Asks,
+		})
+
+		bids, asks, err := s.orderBooks.GetBook(update.Symbol)
+		if err != nil {
+			continue
+		}
+
+		frame := map[string]interface{}{
+			"type": "book",
+			"data": map[string]interface{}{
+				"symbol": update.Symbol,
+				"bids":   bids,
+				"asks":   asks,
+			},
+		}
+		s.broadcastHub.BroadcastToSymbol(update.Symbol, frame)
+		s.broadcastHub.BroadcastToTopic("depth:"+update.Symbol, frame)
+	}
+}
+
+// forwardPriceUpdates fans out updates from the shared price streamer to
+// whichever WebSocket clients opted into that symbol.
+func (s *Server) forwardPriceUpdates() {
+	for update := range s.priceStreamer.GetUpdateChannel() {
+		frame := map[string]interface{}{
+			"type": "price",
+			"data": map[string]interface{}{
+				"symbol":    update.Symbol,
+				"price":     update.Price,
+				"timestamp": update.Timestamp,
+			},
+		}
+		s.broadcastHub.BroadcastToSymbol(update.Symbol, frame)
+		s.broadcastHub.BroadcastToTopic("price:"+update.Symbol, frame)
+	}
+}
+
+// exchangeFor resolves the venue a request targets via its ?exchange= query
+// param, falling back to the registry's default (cfg.ExchangeName, or
+// Binance when that's empty) when it's omitted.
+func (s *Server) exchangeFor(c *gin.Context) (exchange.Exchange, error) {
+	return s.exchanges.Get(c.Query("exchange"))
+}
+
 func (s *Server) setupRoutes() {
 	api := s.router.Group("/api")
 	{
@@ -97,20 +328,65 @@ func (s *Server) setupRoutes() {
 		})
 		api.GET("/price", s.handlePriceWebSocket)
 		api.GET("/kline", s.handleKline)
-		api.GET("/kline/history", s.handleKlineHistory)   // Historical data
-		api.GET("/symbols", s.handleGetSymbols)           // Available symbols
+		api.GET("/klines", s.handleKlines)                     // Rolling in-memory candle window
+		api.GET("/kline/history", s.handleKlineHistory)        // Historical data
+		api.GET("/symbols", s.handleGetSymbols)                // Available symbols
 		api.GET("/symbols/default", s.handleGetDefaultSymbols) // Default symbols from config
-		api.GET("/intervals", s.handleGetIntervals)       // Available intervals
+		api.GET("/intervals", s.handleGetIntervals)            // Available intervals
 		api.POST("/order", s.handlePlaceOrder)
 		api.GET("/balance", s.handleGetBalance)
-		api.GET("/orders", s.handleGetOrders)             // Order history
-		api.GET("/orders/open", s.handleGetOpenOrders)    // Open orders
-		api.GET("/trades", s.handleGetTrades)             // Account trade history
-		api.GET("/depth", s.handleGetDepth)               // Order book depth
-		api.GET("/recent-trades", s.handleGetRecentTrades) // Recent public trades
-		api.GET("/prices", s.handleGetAllPrices)          // All symbol prices
-		api.GET("/ticker/24hr", s.handleGet24hrTicker)    // 24hr ticker data
+		api.GET("/orders", s.handleGetOrders)                   // Order history
+		api.GET("/orders/open", s.handleGetOpenOrders)          // Open orders
+		api.GET("/trades", s.handleGetTrades)                   // Account trade history
+		api.GET("/depth", s.handleGetDepth)                     // Order book depth
+		api.GET("/recent-trades", s.handleGetRecentTrades)      // Recent public trades
+		api.GET("/prices", s.handleGetAllPrices)                // All symbol prices
+		api.GET("/ticker/24hr", s.handleGet24hrTicker)          // 24hr ticker data
+		api.GET("/ticker/stream", s.handleTickerWebSocket)      // Live ticker updates, optionally filtered by ?symbol=
+		api.POST("/subscribe", s.handleSubscribe)               // Watch an additional symbol
+		api.POST("/unsubscribe", s.handleUnsubscribe)           // Stop watching a symbol
+		api.GET("/book/:symbol", s.handleGetBook)               // Live local order book
+		api.POST("/config/stream-url", s.handleUpdateStreamURL) // Hot-swap testnet/mainnet stream URL
+		api.GET("/user", s.handleUserWebSocket)                 // Account/order/balance push updates
+		api.GET("/stream", s.handleStreamWebSocket)             // Multiplexed client-driven subscription protocol
+
+		// Futures trading; all return 400 with an explanatory error when the
+		// bot was started with BINANCE_USE_FUTURES unset/false (s.futuresClient == nil).
+		api.POST("/futures/order", s.handleFuturesPlaceOrder)
+		api.GET("/futures/positions", s.handleGetFuturesPositions)         // Open (and flat) position risk
+		api.GET("/futures/funding-rate", s.handleGetFuturesFundingRate)    // ?symbol=
+		api.POST("/futures/margin-type", s.handleSetFuturesMarginType)     // Per-symbol isolated/cross
+		api.POST("/futures/position-mode", s.handleSetFuturesPositionMode) // Account-wide hedge/one-way
+		api.POST("/futures/leverage", s.handleSetFuturesLeverage)          // Per-symbol leverage
+	}
+}
+
+// heikinAshiKlines converts a slice of []client.KlineData (Binance's
+// string-typed OHLC) to Heikin-Ashi candles in place, reusing
+// websocket.ToHeikinAshi for the actual recurrence. It lives here rather than
+// in the websocket package because it's the only place both client.KlineData
+// and the HA transform meet.
+func heikinAshiKlines(klines []client.KlineData) []client.KlineData {
+	candles := make([]websocket.HeikinAshiCandle, len(klines))
+	for i, k := range klines {
+		open, _ := strconv.ParseFloat(k.Open, 64)
+		high, _ := strconv.ParseFloat(k.High, 64)
+		low, _ := strconv.ParseFloat(k.Low, 64)
+		close, _ := strconv.ParseFloat(k.Close, 64)
+		candles[i] = websocket.HeikinAshiCandle{Open: open, High: high, Low: low, Close: close}
+	}
+
+	ha := websocket.ToHeikinAshi(candles)
+
+	out := make([]client.KlineData, len(klines))
+	for i, k := range klines {
+		out[i] = k
+		out[i].Open = strconv.FormatFloat(ha[i].Open, 'f', 8, 64)
+		out[i].High = strconv.FormatFloat(ha[i].High, 'f', 8, 64)
+		out[i].Low = strconv.FormatFloat(ha[i].Low, 'f', 8, 64)
+		out[i].Close = strconv.FormatFloat(ha[i].Close, 'f', 8, 64)
 	}
+	return out
 }
 
 // handleKlineHistory handles historical data requests with custom date ranges
@@ -120,6 +396,7 @@ func (s *Server) handleKlineHistory(c *gin.Context) {
 	startTime := c.Query("startTime")
 	endTime := c.Query("endTime")
 	limit := c.DefaultQuery("limit", "500")
+	heikinAshi := c.Query("candleType") == "heikinashi"
 
 	log.Printf("📊 Historical data request: %s %s (limit: %s)", symbol, interval, limit)
 
@@ -133,6 +410,9 @@ func (s *Server) handleKlineHistory(c *gin.Context) {
 			})
 			return
 		}
+		if heikinAshi {
+			klines = heikinAshiKlines(klines)
+		}
 		c.JSON(http.StatusOK, gin.H{
 			"data":  klines,
 			"count": len(klines),
@@ -141,8 +421,15 @@ func (s *Server) handleKlineHistory(c *gin.Context) {
 		return
 	}
 
-	// Otherwise, use regular limit-based fetch
-	klines, err := s.tradingClient.GetKlines(symbol, interval, limit)
+	// Otherwise, use regular limit-based fetch against whichever venue the
+	// request targets (?exchange=, default from the registry).
+	ex, err := s.exchangeFor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	klines, err := ex.GetKlines(symbol, interval, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   err.Error(),
@@ -150,22 +437,30 @@ func (s *Server) handleKlineHistory(c *gin.Context) {
 		})
 		return
 	}
+	if heikinAshi {
+		klines = heikinAshiKlines(klines)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":  klines,
-		"count": len(klines),
-		"source": "api",
+		"data":   klines,
+		"count":  len(klines),
+		"source": ex.Name(),
 	})
 }
 
 // handleGetSymbols returns available trading symbols from Binance
 func (s *Server) handleGetSymbols(c *gin.Context) {
-	// Fetch real symbols from Binance API
-	prices, err := s.tradingClient.GetSymbolPrices()
+	ex, err := s.exchangeFor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prices, err := ex.GetSymbolPrices()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   err.Error(),
-			"message": "Failed to fetch symbols from Binance",
+			"message": "Failed to fetch symbols from " + ex.Name(),
 		})
 		return
 	}
@@ -182,7 +477,7 @@ func (s *Server) handleGetSymbols(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"symbols": symbols,
 		"count":   len(symbols),
-		"source":  "binance-api",
+		"source":  ex.Name(),
 	})
 }
 
@@ -213,7 +508,7 @@ func (s *Server) handleGetIntervals(c *gin.Context) {
 func (s *Server) handleGetOrders(c *gin.Context) {
 	symbol := c.DefaultQuery("symbol", "BTCUSDT")
 	limitStr := c.DefaultQuery("limit", "20")
-	
+
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
 		limit = 20
@@ -240,7 +535,13 @@ func (s *Server) handleGetOrders(c *gin.Context) {
 func (s *Server) handleGetOpenOrders(c *gin.Context) {
 	symbol := c.DefaultQuery("symbol", "")
 
-	orders, err := s.tradingClient.GetOpenOrders(symbol)
+	ex, err := s.exchangeFor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orders, err := ex.GetOpenOrders(symbol)
 	if err != nil {
 		log.Printf("❌ Failed to fetch open orders: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -268,6 +569,7 @@ func (s *Server) handleKline(c *gin.Context) {
 	symbol := c.DefaultQuery("symbol", "BTCUSDT")
 	interval := c.DefaultQuery("interval", "1m")
 	limit := c.DefaultQuery("limit", "100")
+	heikinAshi := c.Query("candleType") == "heikinashi"
 
 	// First try to get from buffer for faster response
 	s.streamersMux.Lock()
@@ -275,13 +577,16 @@ func (s *Server) handleKline(c *gin.Context) {
 		buffer := streamer.GetBuffer()
 		history := buffer.GetHistory(1000) // Get up to 1000 candles from buffer
 		s.streamersMux.Unlock()
-		
+
 		if len(history) > 0 {
+			if heikinAshi {
+				history = websocket.ToHeikinAshiUpdates(history)
+			}
 			log.Printf("📊 Serving %d candles from buffer for %s", len(history), symbol)
 			c.JSON(http.StatusOK, gin.H{
-				"data": history,
+				"data":   history,
 				"source": "buffer",
-				"count": len(history),
+				"count":  len(history),
 			})
 			return
 		}
@@ -297,12 +602,45 @@ func (s *Server) handleKline(c *gin.Context) {
 		})
 		return
 	}
+	if heikinAshi {
+		klines = heikinAshiKlines(klines)
+	}
 
 	log.Printf("📊 Fetched %d klines from API for %s", len(klines), symbol)
 	c.JSON(http.StatusOK, gin.H{
-		"data": klines,
+		"data":   klines,
 		"source": "api",
-		"count": len(klines),
+		"count":  len(klines),
+	})
+}
+
+// handleKlines serves the rolling in-memory candle window maintained by a
+// symbol+interval's KlineStreamer, starting the streamer (and its REST
+// backfill) on first request if it isn't already running.
+func (s *Server) handleKlines(c *gin.Context) {
+	symbol := c.DefaultQuery("symbol", "BTCUSDT")
+	interval := c.DefaultQuery("interval", "1m")
+
+	s.ensureKlineStreamerRunning(symbol, interval)
+
+	s.streamersMux.Lock()
+	streamer, exists := s.klineStreamers[symbol+"_"+interval]
+	s.streamersMux.Unlock()
+
+	if !exists {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start kline streamer"})
+		return
+	}
+
+	history := streamer.GetBuffer().GetHistory(config.AppConfig.MaxCandles)
+	if c.Query("candleType") == "heikinashi" {
+		history = websocket.ToHeikinAshiUpdates(history)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":     history,
+		"symbol":   symbol,
+		"interval": interval,
+		"count":    len(history),
 	})
 }
 
@@ -364,9 +702,9 @@ func (s *Server) handleKlineWebSocket(c *gin.Context) {
 	}
 
 	// Register client with broadcast hub for enhanced broadcasting
-	client := s.broadcastHub.RegisterClient(conn, symbol, interval)
+	client := s.broadcastHub.RegisterClientWithOptions(conn, symbol, interval, websocket.ClientOptions{Encoding: websocket.ParseEncoding(c.Query("encoding"))})
 
-	log.Printf("🔌 New Kline WebSocket client connected for %s %s (Total: %d)", 
+	log.Printf("🔌 New Kline WebSocket client connected for %s %s (Total: %d)",
 		symbol, interval, s.broadcastHub.GetClientCount())
 
 	// Start kline streamer for this symbol+interval if not already running
@@ -398,6 +736,30 @@ func (s *Server) handleKlineWebSocket(c *gin.Context) {
 	}
 }
 
+// handleUserWebSocket upgrades HTTP to WebSocket for account/order/balance
+// push updates from the User Data Stream.
+func (s *Server) handleUserWebSocket(c *gin.Context) {
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		return
+	}
+
+	client := s.broadcastHub.RegisterClientWithOptions(conn, userStreamTopic, "", websocket.ClientOptions{Encoding: websocket.ParseEncoding(c.Query("encoding"))})
+	log.Printf("🔌 New user stream client connected (Total: %d)", s.broadcastHub.GetClientCount())
+
+	defer func() {
+		s.broadcastHub.UnregisterClient(client)
+		log.Printf("🔌 User stream client disconnected (Remaining: %d)", s.broadcastHub.GetClientCount())
+	}()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+}
+
 // ensureKlineStreamerRunning starts a kline streamer for the symbol+interval if not already running
 func (s *Server) ensureKlineStreamerRunning(symbol, interval string) {
 	s.streamersMux.Lock()
@@ -411,7 +773,7 @@ func (s *Server) ensureKlineStreamerRunning(symbol, interval string) {
 	}
 
 	// Create and start new kline streamer
-	streamer := websocket.NewKlineStreamer(symbol, interval)
+	streamer := websocket.NewKlineStreamer(symbol, interval, config.AppConfig.MaxCandles, s.tradingClient)
 	if err := streamer.Start(); err != nil {
 		log.Printf("❌ Failed to start kline streamer for %s %s: %v", symbol, interval, err)
 		return
@@ -420,14 +782,31 @@ func (s *Server) ensureKlineStreamerRunning(symbol, interval string) {
 	s.klineStreamers[streamKey] = streamer
 	log.Printf("🚀 Started kline streamer for %s %s", symbol, interval)
 
+	klineTopic := fmt.Sprintf("kline:%s:%s", symbol, interval)
+
 	// Handle kline updates for this symbol+interval
 	go func() {
 		for update := range streamer.GetUpdateChannel() {
 			// Use enhanced broadcasting system
-			s.broadcastHub.BroadcastToSymbol(symbol, map[string]interface{}{
+			frame := map[string]interface{}{
 				"type": "kline",
 				"data": update,
-			})
+			}
+			s.broadcastHub.BroadcastToSymbol(symbol, frame)
+			s.broadcastHub.BroadcastToTopic(klineTopic, frame)
+		}
+	}()
+
+	// Handle Heikin-Ashi updates for this symbol+interval, broadcast as a
+	// distinct "kline_ha" frame so clients can tell the two apart.
+	go func() {
+		for haUpdate := range streamer.GetHAUpdateChannel() {
+			frame := map[string]interface{}{
+				"type": "kline_ha",
+				"data": haUpdate,
+			}
+			s.broadcastHub.BroadcastToSymbol(symbol, frame)
+			s.broadcastHub.BroadcastToTopic("kline_ha:"+symbol+":"+interval, frame)
 		}
 	}()
 
@@ -484,7 +863,7 @@ func (s *Server) ensureStreamerRunning(symbol string) {
 	}
 
 	// Create and start new streamer
-	streamer := websocket.NewPriceStreamer(symbol)
+	streamer := websocket.NewPriceStreamer(s.ctx, []string{symbol}, config.AppConfig.WSReconnectDelay)
 	if err := streamer.Start(); err != nil {
 		log.Printf("❌ Failed to start streamer for %s: %v", symbol, err)
 		return
@@ -554,7 +933,13 @@ func (s *Server) broadcastPrice(symbol, price string, timestamp int64) {
 }
 
 func (s *Server) handleGetBalance(c *gin.Context) {
-	balances, err := s.tradingClient.GetAccountBalance()
+	ex, err := s.exchangeFor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balances, err := ex.GetAccountBalance()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -576,7 +961,7 @@ func (s *Server) handleGetBalance(c *gin.Context) {
 func (s *Server) handleGetTrades(c *gin.Context) {
 	symbol := c.DefaultQuery("symbol", "BTCUSDT")
 	limitStr := c.DefaultQuery("limit", "50")
-	
+
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
 		limit = 50
@@ -599,17 +984,33 @@ func (s *Server) handleGetTrades(c *gin.Context) {
 	})
 }
 
-// handleGetDepth handles retrieving order book depth
+// GetDepthBuffer serves the live local order book for symbol instantly from
+// the orderbook.Manager's depth-diff buffer (REST snapshot + @depth@100ms
+// diffs, with gap detection triggering a resync), seeding it synchronously
+// on first request instead of round-tripping to Binance on every call.
+func (s *Server) GetDepthBuffer(symbol string) (bids, asks []orderbook.Level, err error) {
+	return s.orderBooks.GetBook(symbol)
+}
+
+// handleGetDepth serves order book depth for symbol. A plain GET returns the
+// current snapshot as JSON; a WebSocket upgrade switches to handleDepthWebSocket,
+// which sends that same snapshot once and then the full updated book after
+// every applied diff.
 func (s *Server) handleGetDepth(c *gin.Context) {
-	symbol := c.DefaultQuery("symbol", "BTCUSDT")
+	if c.Request.Header.Get("Upgrade") == "websocket" {
+		s.handleDepthWebSocket(c)
+		return
+	}
+
+	symbol := strings.ToUpper(c.DefaultQuery("symbol", "BTCUSDT"))
 	limitStr := c.DefaultQuery("limit", "100")
-	
+
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
 		limit = 100
 	}
 
-	depth, err := s.tradingClient.GetOrderBookDepth(symbol, limit)
+	bids, asks, err := s.GetDepthBuffer(symbol)
 	if err != nil {
 		log.Printf("❌ Failed to fetch order book depth: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -618,16 +1019,65 @@ func (s *Server) handleGetDepth(c *gin.Context) {
 		return
 	}
 
-	log.Printf("📊 Retrieved order book depth for %s (bids: %d, asks: %d)", 
-		symbol, len(depth.Bids), len(depth.Asks))
+	if limit > 0 && limit < len(bids) {
+		bids = bids[:limit]
+	}
+	if limit > 0 && limit < len(asks) {
+		asks = asks[:limit]
+	}
+
+	// Make sure the depth stream is actually watching this symbol so the
+	// book stays live after this first request.
+	s.bookStreamer.AddSymbol(symbol)
+
+	log.Printf("📊 Retrieved order book depth for %s (bids: %d, asks: %d)",
+		symbol, len(bids), len(asks))
 	c.JSON(http.StatusOK, gin.H{
-		"lastUpdateId": depth.LastUpdateID,
-		"bids":         depth.Bids,
-		"asks":         depth.Asks,
-		"symbol":       symbol,
+		"bids":   bids,
+		"asks":   asks,
+		"symbol": symbol,
 	})
 }
 
+// handleDepthWebSocket upgrades HTTP to WebSocket for live order book depth.
+// It registers the client on the symbol's broadcastHub topic (the same topic
+// forwardBookUpdates already publishes "book"-typed full-book frames to),
+// sends an immediate full snapshot so the client doesn't start empty, then
+// receives a fresh full book after every applied diff.
+func (s *Server) handleDepthWebSocket(c *gin.Context) {
+	symbol := strings.ToUpper(c.DefaultQuery("symbol", "BTCUSDT"))
+
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		return
+	}
+
+	client := s.broadcastHub.RegisterClientWithOptions(conn, symbol, "", websocket.ClientOptions{Encoding: websocket.ParseEncoding(c.Query("encoding"))})
+	log.Printf("🔌 New depth WebSocket client connected for %s (Total: %d)", symbol, s.broadcastHub.GetClientCount())
+
+	s.bookStreamer.AddSymbol(symbol)
+
+	if bids, asks, err := s.GetDepthBuffer(symbol); err == nil {
+		s.broadcastHub.SendSnapshot(client, map[string]interface{}{
+			"symbol": symbol,
+			"bids":   bids,
+			"asks":   asks,
+		})
+	}
+
+	defer func() {
+		s.broadcastHub.UnregisterClient(client)
+		log.Printf("🔌 Depth client disconnected (Remaining: %d)", s.broadcastHub.GetClientCount())
+	}()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+}
+
 func (s *Server) handlePlaceOrder(c *gin.Context) {
 	var req OrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -641,7 +1091,13 @@ func (s *Server) handlePlaceOrder(c *gin.Context) {
 	// Handle different order types
 	switch req.Type {
 	case "MARKET":
-		result, err = s.tradingClient.PlaceMarketOrder(req.Symbol, req.Side, req.Quantity)
+		var ex exchange.Exchange
+		ex, err = s.exchangeFor(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result, err = ex.PlaceMarketOrder(req.Symbol, req.Side, req.Quantity)
 	case "LIMIT":
 		if req.Price == "" {
 			c.JSON(http.StatusBadRequest, gin.H{
@@ -689,9 +1145,176 @@ func (s *Server) handlePlaceOrder(c *gin.Context) {
 	})
 }
 
+// futuresPositionSide parses req.PositionSide, defaulting to "BOTH"
+// (one-way mode) when empty.
+func futuresPositionSide(raw string) futures.PositionSideType {
+	if raw == "" {
+		return futures.PositionSideTypeBoth
+	}
+	return futures.PositionSideType(raw)
+}
+
+func (s *Server) handleFuturesPlaceOrder(c *gin.Context) {
+	if s.futuresClient == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "futures trading is not enabled (set BINANCE_USE_FUTURES=true)"})
+		return
+	}
+
+	var req FuturesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	positionSide := futuresPositionSide(req.PositionSide)
+
+	var result *client.OrderResult
+	var err error
+	switch req.Type {
+	case "", "MARKET":
+		result, err = s.futuresClient.PlaceMarketOrder(req.Symbol, req.Side, req.Quantity, positionSide)
+	case "LIMIT":
+		if req.Price == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Price is required for limit orders"})
+			return
+		}
+		result, err = s.futuresClient.PlaceLimitOrder(req.Symbol, req.Side, req.Quantity, req.Price, positionSide)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order type", "message": "Type must be MARKET or LIMIT"})
+		return
+	}
+
+	if err != nil {
+		log.Printf("❌ Failed to place futures order: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": result})
+}
+
+// handleGetFuturesPositions reports position risk for every futures symbol,
+// including flat ones (see FuturesTradingClient.GetPositionRisk).
+func (s *Server) handleGetFuturesPositions(c *gin.Context) {
+	if s.futuresClient == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "futures trading is not enabled (set BINANCE_USE_FUTURES=true)"})
+		return
+	}
+
+	positions, err := s.futuresClient.GetPositionRisk()
+	if err != nil {
+		log.Printf("❌ Failed to fetch futures positions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"positions": positions, "count": len(positions)})
+}
+
+func (s *Server) handleGetFuturesFundingRate(c *gin.Context) {
+	if s.futuresClient == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "futures trading is not enabled (set BINANCE_USE_FUTURES=true)"})
+		return
+	}
+
+	symbol := c.Query("symbol")
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol is required"})
+		return
+	}
+
+	rate, err := s.futuresClient.GetFundingRate(symbol)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+func (s *Server) handleSetFuturesMarginType(c *gin.Context) {
+	if s.futuresClient == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "futures trading is not enabled (set BINANCE_USE_FUTURES=true)"})
+		return
+	}
+
+	var req FuturesMarginTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.futuresClient.SetMarginType(req.Symbol, req.Isolated); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (s *Server) handleSetFuturesPositionMode(c *gin.Context) {
+	if s.futuresClient == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "futures trading is not enabled (set BINANCE_USE_FUTURES=true)"})
+		return
+	}
+
+	var req FuturesPositionModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.futuresClient.SetPositionMode(req.Hedge); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (s *Server) handleSetFuturesLeverage(c *gin.Context) {
+	if s.futuresClient == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "futures trading is not enabled (set BINANCE_USE_FUTURES=true)"})
+		return
+	}
+
+	var req FuturesLeverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.futuresClient.SetLeverage(req.Symbol, req.Leverage); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Start runs the HTTP server, blocking until it exits via Shutdown or fails
+// to bind. It owns an explicit *http.Server (rather than router.Run) so
+// Shutdown can drain in-flight requests instead of killing them outright.
 func (s *Server) Start(port string) error {
+	s.httpServer = &http.Server{
+		Addr:    ":" + port,
+		Handler: s.router,
+	}
+
 	log.Printf("🚀 Starting HTTP server on :%s", port)
-	return s.router.Run(":" + port)
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the HTTP server, giving in-flight requests up to
+// ctx's deadline to finish before forcing the listener closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
 }
 
 // handleGetRecentTrades handles recent public trades requests
@@ -718,6 +1341,92 @@ func (s *Server) handleGetRecentTrades(c *gin.Context) {
 	})
 }
 
+// handleGetBook serves the live local order book maintained from the
+// @depth diff stream, seeding it with a REST snapshot on first request for a
+// symbol rather than hitting Binance on every call.
+func (s *Server) handleGetBook(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	bids, asks, err := s.orderBooks.GetBook(symbol)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Make sure the depth stream is actually watching this symbol so the
+	// book stays live after this first request.
+	s.bookStreamer.AddSymbol(symbol)
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"bids":   bids,
+		"asks":   asks,
+	})
+}
+
+// StreamURLRequest selects which combined-stream endpoint the shared price
+// streamer should dial, without restarting the process.
+type StreamURLRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// handleUpdateStreamURL hot-swaps the shared price streamer's upstream
+// endpoint (e.g. flipping between testnet and mainnet after Binance rotates
+// its stream URLs) without dropping the current subscription set.
+func (s *Server) handleUpdateStreamURL(c *gin.Context) {
+	var req StreamURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.priceStreamer.UpdateURL(req.URL)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "stream URL updated",
+		"url":     req.URL,
+	})
+}
+
+// handleSubscribe adds a symbol to the shared combined-stream price streamer
+// so it starts flowing to any WebSocket client watching it.
+func (s *Server) handleSubscribe(c *gin.Context) {
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.priceStreamer.AddSymbol(req.Symbol); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("subscribed to %s", strings.ToUpper(req.Symbol)),
+		"symbols": s.priceStreamer.Symbols(),
+	})
+}
+
+// handleUnsubscribe removes a symbol from the shared price streamer.
+func (s *Server) handleUnsubscribe(c *gin.Context) {
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.priceStreamer.RemoveSymbol(req.Symbol); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("unsubscribed from %s", strings.ToUpper(req.Symbol)),
+		"symbols": s.priceStreamer.Symbols(),
+	})
+}
+
 // Cleanup stops all active streamers
 func (s *Server) Cleanup() {
 	// Stop broadcast hub
@@ -725,6 +1434,26 @@ func (s *Server) Cleanup() {
 		s.broadcastHub.Stop()
 	}
 
+	if s.priceStreamer != nil {
+		s.priceStreamer.Stop()
+	}
+
+	if s.bookStreamer != nil {
+		s.bookStreamer.Stop()
+	}
+
+	if s.userStreamer != nil {
+		s.userStreamer.Stop()
+	}
+
+	if s.tickerStreamer != nil {
+		s.tickerStreamer.Stop()
+	}
+
+	if s.multiStreamer != nil {
+		s.multiStreamer.Stop()
+	}
+
 	s.streamersMux.Lock()
 	defer s.streamersMux.Unlock()
 
@@ -743,7 +1472,13 @@ func (s *Server) Cleanup() {
 
 // handleGetAllPrices returns all symbol prices
 func (s *Server) handleGetAllPrices(c *gin.Context) {
-	prices, err := s.tradingClient.GetSymbolPrices()
+	ex, err := s.exchangeFor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prices, err := ex.GetSymbolPrices()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   err.Error(),
@@ -758,7 +1493,9 @@ func (s *Server) handleGetAllPrices(c *gin.Context) {
 	})
 }
 
-// handleGet24hrTicker returns 24hr ticker statistics for multiple symbols
+// handleGet24hrTicker returns 24hr ticker statistics for multiple symbols,
+// served in O(1) from the in-memory cache TickerStreamer maintains off
+// Binance's !ticker@arr all-market stream.
 func (s *Server) handleGet24hrTicker(c *gin.Context) {
 	symbols := c.QueryArray("symbols")
 	if len(symbols) == 0 {
@@ -770,45 +1507,11 @@ func (s *Server) handleGet24hrTicker(c *gin.Context) {
 		}
 	}
 
-	type TickerData struct {
-		Symbol    string  `json:"symbol"`
-		Price     string  `json:"price"`
-		Change24h  float64 `json:"change24h"`
-		High24h   string  `json:"high24h"`
-		Low24h    string  `json:"low24h"`
-		Volume24h string  `json:"volume24h"`
-	}
-
-	var tickers []TickerData
-	
+	tickers := make([]websocket.Ticker24h, 0, len(symbols))
 	for _, symbol := range symbols {
-		// Get current price
-		prices, err := s.tradingClient.GetSymbolPrices()
-		if err != nil {
-			continue
-		}
-		
-		var currentPrice string
-		for _, price := range prices {
-			if price.Symbol == symbol {
-				currentPrice = price.Price
-				break
-			}
-		}
-		
-		if currentPrice == "" {
-			continue
+		if ticker, ok := s.tickerStreamer.Get(symbol); ok {
+			tickers = append(tickers, ticker)
 		}
-		
-		// Get 24hr statistics (simplified - in production you'd use Binance's 24hr ticker API)
-		tickers = append(tickers, TickerData{
-			Symbol:    symbol,
-			Price:     currentPrice,
-			Change24h:  0.0, // Would calculate from real data
-			High24h:   currentPrice,
-			Low24h:    currentPrice,
-			Volume24h: "0",
-		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -816,3 +1519,43 @@ func (s *Server) handleGet24hrTicker(c *gin.Context) {
 		"count":   len(tickers),
 	})
 }
+
+// handleTickerWebSocket upgrades HTTP to WebSocket for live 24hr ticker
+// updates. With ?symbol= set, the client only receives that symbol's
+// updates; without it, the client receives every symbol's updates, for a
+// market overview grid.
+func (s *Server) handleTickerWebSocket(c *gin.Context) {
+	symbol := strings.ToUpper(c.Query("symbol"))
+	topic := tickerAllTopic
+	if symbol != "" {
+		topic = symbol
+	}
+
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		return
+	}
+
+	client := s.broadcastHub.RegisterClientWithOptions(conn, topic, "", websocket.ClientOptions{Encoding: websocket.ParseEncoding(c.Query("encoding"))})
+	log.Printf("🔌 New ticker WebSocket client connected for %s (Total: %d)", topic, s.broadcastHub.GetClientCount())
+
+	if symbol != "" {
+		if ticker, ok := s.tickerStreamer.Get(symbol); ok {
+			s.broadcastHub.SendSnapshot(client, map[string]interface{}{"ticker": ticker})
+		}
+	} else {
+		s.broadcastHub.SendSnapshot(client, map[string]interface{}{"tickers": s.tickerStreamer.GetAll()})
+	}
+
+	defer func() {
+		s.broadcastHub.UnregisterClient(client)
+		log.Printf("🔌 Ticker client disconnected (Remaining: %d)", s.broadcastHub.GetClientCount())
+	}()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aphis/24hrt-backend/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+// streamControlMessage mirrors Binance's own combined-stream JSON-RPC
+// request format, reused here as the client->server control protocol for
+// the multiplexed /api/stream endpoint.
+type streamControlMessage struct {
+	ID     int64    `json:"id"`
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+}
+
+// streamControlReply is sent back for every control message: result is null
+// on success (or the topic list for LIST_SUBSCRIPTIONS), and error is set on
+// failure.
+type streamControlReply struct {
+	ID     int64       `json:"id"`
+	Result interface{} `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// handleStreamWebSocket upgrades HTTP to a single multiplexed WebSocket
+// connection. Clients drive their own subscription set with JSON control
+// messages — SUBSCRIBE/UNSUBSCRIBE/LIST_SUBSCRIPTIONS — instead of needing
+// one socket per symbol/interval:
+//
+//	{"id":1,"method":"SUBSCRIBE","params":["kline:BTCUSDT:1m","price:ETHUSDT","depth:SOLUSDT"]}
+//
+// Each topic is "<type>:<symbol>[:<interval>]" for kline/kline_ha, or
+// "<type>:<symbol>" for price/depth/ticker, or bare "ticker" for every
+// symbol. Upstream events are routed to exactly the clients subscribed to
+// their topic via BroadcastHub.BroadcastToTopic.
+func (s *Server) handleStreamWebSocket(c *gin.Context) {
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade connection: %v", err)
+		return
+	}
+
+	client := s.broadcastHub.RegisterClientWithOptions(conn, "", "", websocket.ClientOptions{Encoding: websocket.ParseEncoding(c.Query("encoding"))})
+	log.Printf("🔌 New multiplexed stream client connected (Total: %d)", s.broadcastHub.GetClientCount())
+
+	defer func() {
+		s.broadcastHub.UnregisterClient(client)
+		log.Printf("🔌 Multiplexed stream client disconnected (Remaining: %d)", s.broadcastHub.GetClientCount())
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var msg streamControlMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			s.broadcastHub.SendToClient(client, streamControlReply{Error: "invalid control message"})
+			continue
+		}
+
+		reply := streamControlReply{ID: msg.ID}
+		switch msg.Method {
+		case "SUBSCRIBE":
+			for _, topic := range msg.Params {
+				if err := s.subscribeStreamTopic(client, topic); err != nil {
+					reply.Error = err.Error()
+					break
+				}
+			}
+		case "UNSUBSCRIBE":
+			for _, topic := range msg.Params {
+				client.Unsubscribe(topic)
+			}
+		case "LIST_SUBSCRIPTIONS":
+			reply.Result = client.Topics()
+		default:
+			reply.Error = fmt.Sprintf("unknown method %q", msg.Method)
+		}
+
+		s.broadcastHub.SendToClient(client, reply)
+	}
+}
+
+// subscribeStreamTopic validates topic, starts whichever upstream streamer
+// it depends on (if not already running), and adds it to client's
+// multiplexed subscription set.
+func (s *Server) subscribeStreamTopic(client *websocket.ClientConnection, topic string) error {
+	parts := strings.Split(topic, ":")
+	kind := parts[0]
+
+	switch kind {
+	case "kline", "kline_ha":
+		if len(parts) < 2 {
+			return fmt.Errorf("topic %q missing symbol", topic)
+		}
+		symbol := strings.ToUpper(parts[1])
+		interval := "1m"
+		if len(parts) >= 3 {
+			interval = parts[2]
+		}
+		// Store the topic in the form the kline forwarders broadcast to.
+		topic = kind + ":" + symbol + ":" + interval
+		s.ensureKlineStreamerRunning(symbol, interval)
+
+	case "price":
+		if len(parts) < 2 {
+			return fmt.Errorf("topic %q missing symbol", topic)
+		}
+		if err := s.priceStreamer.AddSymbol(parts[1]); err != nil {
+			return err
+		}
+
+	case "depth":
+		if len(parts) < 2 {
+			return fmt.Errorf("topic %q missing symbol", topic)
+		}
+		s.bookStreamer.AddSymbol(strings.ToUpper(parts[1]))
+
+	case "ticker":
+		// Already running globally; nothing to start per-symbol.
+
+	default:
+		return fmt.Errorf("unknown topic type %q", kind)
+	}
+
+	client.Subscribe(topic)
+	return nil
+}
@@ -0,0 +1,354 @@
+// Package userstream implements Binance's User Data Stream: listen-key
+// lifecycle management plus a WebSocket connection that decodes account,
+// balance, and order events so the trading engine never has to poll
+// GetAccountBalance / GetOpenOrders on a timer.
+package userstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+)
+
+const (
+	mainnetWSBase = "wss://stream.binance.com:9443/ws/"
+	testnetWSBase = "wss://testnet.binance.vision/ws/"
+
+	// keepAliveInterval is how often the listen key is refreshed. Binance
+	// expires an unrefreshed key after 60 minutes.
+	keepAliveInterval = 30 * time.Minute
+)
+
+// ListenKeyManager is the subset of client.TradingClient needed to drive a
+// user data stream's listen key lifecycle. Declared as an interface here
+// (rather than importing client directly) to keep this package usable
+// against a mock in tests.
+type ListenKeyManager interface {
+	CreateListenKey() (string, error)
+	KeepAliveListenKey(listenKey string) error
+	CloseListenKey(listenKey string) error
+}
+
+// OrderUpdate is a parsed `executionReport` event.
+type OrderUpdate struct {
+	Symbol        string `json:"symbol"`
+	ClientOrderID string `json:"clientOrderId"`
+	OrderID       int64  `json:"orderId"`
+	Side          string `json:"side"`
+	OrderType     string `json:"orderType"`
+	Status        string `json:"status"`
+	Price         string `json:"price"`
+	Quantity      string `json:"quantity"`
+	ExecutedQty   string `json:"executedQty"`
+	LastFilledQty string `json:"lastFilledQty"`
+	LastFillPrice string `json:"lastFillPrice"`
+	EventTime     int64  `json:"eventTime"`
+}
+
+// BalanceInfo is a single asset entry from an `outboundAccountPosition`
+// event.
+type BalanceInfo struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+type rawExecutionReport struct {
+	EventType            string `json:"e"`
+	EventTime            int64  `json:"E"`
+	Symbol               string `json:"s"`
+	ClientOrderID        string `json:"c"`
+	Side                 string `json:"S"`
+	OrderType            string `json:"o"`
+	Quantity             string `json:"q"`
+	Price                string `json:"p"`
+	OrderStatus          string `json:"X"`
+	OrderID              int64  `json:"i"`
+	LastExecutedQuantity string `json:"l"`
+	LastExecutedPrice    string `json:"L"`
+	CumulativeFilledQty  string `json:"z"`
+}
+
+type rawBalance struct {
+	Asset  string `json:"a"`
+	Free   string `json:"f"`
+	Locked string `json:"l"`
+}
+
+type rawOutboundAccountPosition struct {
+	EventType string       `json:"e"`
+	EventTime int64        `json:"E"`
+	Balances  []rawBalance `json:"B"`
+}
+
+type rawBalanceUpdate struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Asset     string `json:"a"`
+	Delta     string `json:"d"`
+}
+
+type rawEventType struct {
+	EventType string `json:"e"`
+}
+
+// UserStreamer manages listen-key lifecycle and the resulting WebSocket
+// connection, re-establishing both on disconnect with exponential backoff.
+type UserStreamer struct {
+	manager   ListenKeyManager
+	wsBaseURL string
+
+	listenKey   string
+	listenKeyMu sync.Mutex
+
+	orderChan   chan OrderUpdate
+	balanceChan chan BalanceInfo
+	errorChan   chan error
+	stopChan    chan struct{}
+	isRunning   bool
+}
+
+// NewUserStreamer creates a streamer against the given listen-key manager.
+// isTestnet selects the matching WebSocket base URL.
+func NewUserStreamer(manager ListenKeyManager, isTestnet bool) *UserStreamer {
+	base := mainnetWSBase
+	if isTestnet {
+		base = testnetWSBase
+	}
+
+	return &UserStreamer{
+		manager:     manager,
+		wsBaseURL:   base,
+		orderChan:   make(chan OrderUpdate, 100),
+		balanceChan: make(chan BalanceInfo, 100),
+		errorChan:   make(chan error, 10),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start obtains a listen key and begins streaming account events.
+func (us *UserStreamer) Start() error {
+	if us.isRunning {
+		return fmt.Errorf("user streamer already running")
+	}
+
+	listenKey, err := us.manager.CreateListenKey()
+	if err != nil {
+		return err
+	}
+
+	us.listenKeyMu.Lock()
+	us.listenKey = listenKey
+	us.listenKeyMu.Unlock()
+
+	us.isRunning = true
+	go us.keepAliveLoop()
+	go us.startStream()
+
+	log.Println("🚀 Started user data stream")
+	return nil
+}
+
+// Stop closes the listen key and tears down the stream.
+func (us *UserStreamer) Stop() {
+	if !us.isRunning {
+		return
+	}
+
+	close(us.stopChan)
+	us.isRunning = false
+
+	us.listenKeyMu.Lock()
+	listenKey := us.listenKey
+	us.listenKeyMu.Unlock()
+
+	if listenKey != "" {
+		if err := us.manager.CloseListenKey(listenKey); err != nil {
+			log.Printf("⚠️  Failed to close listen key: %v", err)
+		}
+	}
+
+	log.Println("🛑 Stopped user data stream")
+}
+
+// GetOrderUpdateChannel returns the channel of order fill/status updates.
+func (us *UserStreamer) GetOrderUpdateChannel() <-chan OrderUpdate {
+	return us.orderChan
+}
+
+// GetBalanceUpdateChannel returns the channel of balance changes.
+func (us *UserStreamer) GetBalanceUpdateChannel() <-chan BalanceInfo {
+	return us.balanceChan
+}
+
+// GetErrorChannel returns the channel for errors.
+func (us *UserStreamer) GetErrorChannel() <-chan error {
+	return us.errorChan
+}
+
+// keepAliveLoop pings the listen key every 30 minutes so Binance doesn't
+// expire it at the 60-minute mark.
+func (us *UserStreamer) keepAliveLoop() {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-us.stopChan:
+			return
+		case <-ticker.C:
+			us.listenKeyMu.Lock()
+			listenKey := us.listenKey
+			us.listenKeyMu.Unlock()
+
+			if listenKey == "" {
+				continue
+			}
+			if err := us.manager.KeepAliveListenKey(listenKey); err != nil {
+				log.Printf("⚠️  Listen key keepalive failed: %v", err)
+				select {
+				case us.errorChan <- err:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// startStream dials the user data stream and reconnects (rotating the
+// listen key) on disconnect, waiting with exponential backoff (5s doubling
+// up to 60s) between attempts. A connection that stayed up longer than the
+// cap resets the wait to 5s.
+func (us *UserStreamer) startStream() {
+	const baseBackoff, maxBackoff = 5 * time.Second, 60 * time.Second
+	delay := baseBackoff
+	for {
+		select {
+		case <-us.stopChan:
+			return
+		default:
+			started := time.Now()
+			us.connectAndListen()
+			if time.Since(started) > maxBackoff {
+				delay = baseBackoff
+			}
+
+			select {
+			case <-us.stopChan:
+				return
+			case <-time.After(delay):
+			}
+			if delay *= 2; delay > maxBackoff {
+				delay = maxBackoff
+			}
+
+			// Rotate the listen key on reconnect — the old one may have
+			// expired or been invalidated by Binance while we were down.
+			listenKey, err := us.manager.CreateListenKey()
+			if err != nil {
+				log.Printf("❌ Failed to rotate listen key: %v", err)
+				continue
+			}
+			us.listenKeyMu.Lock()
+			us.listenKey = listenKey
+			us.listenKeyMu.Unlock()
+			log.Println("♻️  Rotated user data stream listen key, reconnecting...")
+		}
+	}
+}
+
+func (us *UserStreamer) connectAndListen() {
+	us.listenKeyMu.Lock()
+	listenKey := us.listenKey
+	us.listenKeyMu.Unlock()
+
+	if listenKey == "" {
+		return
+	}
+
+	conn, _, err := ws.DefaultDialer.Dial(us.wsBaseURL+listenKey, nil)
+	if err != nil {
+		log.Printf("❌ Failed to dial user data stream: %v", err)
+		us.errorChan <- err
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				log.Printf("⚠️  User data stream read error: %v", err)
+				return
+			}
+			us.handleEvent(message)
+		}
+	}()
+
+	select {
+	case <-us.stopChan:
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return
+	case <-done:
+		return
+	}
+}
+
+func (us *UserStreamer) handleEvent(message []byte) {
+	var eventType rawEventType
+	if err := json.Unmarshal(message, &eventType); err != nil {
+		return
+	}
+
+	switch eventType.EventType {
+	case "executionReport":
+		var raw rawExecutionReport
+		if err := json.Unmarshal(message, &raw); err != nil {
+			return
+		}
+		update := OrderUpdate{
+			Symbol:        raw.Symbol,
+			ClientOrderID: raw.ClientOrderID,
+			OrderID:       raw.OrderID,
+			Side:          raw.Side,
+			OrderType:     raw.OrderType,
+			Status:        raw.OrderStatus,
+			Price:         raw.Price,
+			Quantity:      raw.Quantity,
+			ExecutedQty:   raw.CumulativeFilledQty,
+			LastFilledQty: raw.LastExecutedQuantity,
+			LastFillPrice: raw.LastExecutedPrice,
+			EventTime:     raw.EventTime,
+		}
+		select {
+		case us.orderChan <- update:
+			log.Printf("📝 Order update: %s %s %s (%s)", update.Symbol, update.Side, update.Status, update.OrderType)
+		default:
+		}
+
+	case "outboundAccountPosition":
+		var raw rawOutboundAccountPosition
+		if err := json.Unmarshal(message, &raw); err != nil {
+			return
+		}
+		for _, balance := range raw.Balances {
+			select {
+			case us.balanceChan <- BalanceInfo{Asset: balance.Asset, Free: balance.Free, Locked: balance.Locked}:
+			default:
+			}
+		}
+
+	case "balanceUpdate":
+		var raw rawBalanceUpdate
+		if err := json.Unmarshal(message, &raw); err != nil {
+			return
+		}
+		log.Printf("💰 Balance delta: %s %s", raw.Asset, raw.Delta)
+	}
+}
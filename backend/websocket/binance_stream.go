@@ -0,0 +1,107 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+)
+
+// BinanceStream adapts the existing per-concern streamers — MultiStreamer
+// for klines, PriceStreamer for trades, BookStreamer for depth — to the
+// single ExchangeStream surface. It wraps already-constructed streamers
+// (typically the ones the server already runs) rather than owning its own
+// connections, so plugging Binance behind ExchangeStream doesn't double the
+// number of live WebSocket connections to Binance.
+type BinanceStream struct {
+	StandardStream
+
+	multi *MultiStreamer
+	price *PriceStreamer
+	book  *BookStreamer
+
+	startOnce sync.Once
+}
+
+// NewBinanceStream wraps multi/price/book. Any of the three may be nil if
+// that feed isn't needed; Subscribe/Connect skip whichever are absent.
+func NewBinanceStream(multi *MultiStreamer, price *PriceStreamer, book *BookStreamer) *BinanceStream {
+	return &BinanceStream{multi: multi, price: price, book: book}
+}
+
+func (bs *BinanceStream) Name() string { return "binance" }
+
+// Connect starts forwarding the trade and depth streamers' shared update
+// channels into this stream's OnTrade/OnDepth callbacks. It's idempotent.
+// Kline forwarding is wired per-subscription inside Subscribe instead, since
+// MultiStreamer hands back a dedicated channel per symbol/interval rather
+// than one shared firehose.
+func (bs *BinanceStream) Connect(ctx context.Context) error {
+	bs.startOnce.Do(func() {
+		if bs.price != nil {
+			go func() {
+				for update := range bs.price.GetUpdateChannel() {
+					bs.EmitTrade(update)
+				}
+			}()
+		}
+		if bs.book != nil {
+			go func() {
+				for update := range bs.book.GetUpdateChannel() {
+					bs.EmitDepth(update)
+				}
+			}()
+		}
+	})
+	return nil
+}
+
+// Subscribe starts watching symbol's trades and depth, and, when interval is
+// non-empty, also its kline stream.
+func (bs *BinanceStream) Subscribe(symbol, interval string) error {
+	if bs.price != nil {
+		if err := bs.price.AddSymbol(symbol); err != nil {
+			return err
+		}
+	}
+	if bs.book != nil {
+		if err := bs.book.AddSymbol(symbol); err != nil {
+			return err
+		}
+	}
+	if interval != "" && bs.multi != nil {
+		updateChan, err := bs.multi.Subscribe(symbol, interval)
+		if err != nil {
+			return err
+		}
+		go func() {
+			for update := range updateChan {
+				bs.EmitKline(update)
+			}
+		}()
+	}
+
+	bs.addSubscription(subscriptionKey(symbol, interval))
+	return nil
+}
+
+// Unsubscribe stops watching symbol's trades/depth and, when interval is
+// non-empty, its kline stream.
+func (bs *BinanceStream) Unsubscribe(symbol, interval string) error {
+	if bs.price != nil {
+		if err := bs.price.RemoveSymbol(symbol); err != nil {
+			return err
+		}
+	}
+	if bs.book != nil {
+		if err := bs.book.RemoveSymbol(symbol); err != nil {
+			return err
+		}
+	}
+	if interval != "" && bs.multi != nil {
+		if err := bs.multi.Unsubscribe(symbol, interval); err != nil {
+			return err
+		}
+	}
+
+	bs.removeSubscription(subscriptionKey(symbol, interval))
+	return nil
+}
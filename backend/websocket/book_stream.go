@@ -0,0 +1,256 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+)
+
+// DepthDiffEvent is a parsed Binance `@depth` diff update.
+type DepthDiffEvent struct {
+	Symbol        string
+	FirstUpdateID int64 // U
+	FinalUpdateID int64 // u
+	Bids          [][]string
+	Asks          [][]string
+}
+
+type rawDepthEvent struct {
+	EventType     string     `json:"e"`
+	Symbol        string     `json:"s"`
+	FirstUpdateID int64      `json:"U"`
+	FinalUpdateID int64      `json:"u"`
+	Bids          [][]string `json:"b"`
+	Asks          [][]string `json:"a"`
+}
+
+// BookStreamer maintains a single combined WebSocket connection covering the
+// `@depth` diff stream for every watched symbol, feeding raw diff events to
+// whoever is listening on GetUpdateChannel (typically an orderbook.Manager).
+// It mirrors PriceStreamer's combined-stream + runtime AddSymbol/RemoveSymbol
+// design so the two can share a connection-management mental model.
+type BookStreamer struct {
+	symbols   map[string]bool
+	symbolsMu sync.Mutex
+
+	conn   *ws.Conn
+	connMu sync.Mutex
+
+	updateChan chan DepthDiffEvent
+	errorChan  chan error
+	stopChan   chan struct{}
+	isRunning  bool
+
+	requestID int64
+}
+
+// NewBookStreamer creates a new combined depth-diff streamer for the given
+// initial symbols.
+func NewBookStreamer(symbols []string) *BookStreamer {
+	seed := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		if s == "" {
+			continue
+		}
+		seed[strings.ToUpper(s)] = true
+	}
+
+	return &BookStreamer{
+		symbols:    seed,
+		updateChan: make(chan DepthDiffEvent, 200),
+		errorChan:  make(chan error, 10),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start begins listening to depth-diff updates.
+func (bs *BookStreamer) Start() error {
+	if bs.isRunning {
+		return fmt.Errorf("book streamer already running")
+	}
+
+	bs.isRunning = true
+	go bs.startStream()
+
+	log.Printf("🚀 Started combined depth stream for %d symbol(s)", len(bs.Symbols()))
+	return nil
+}
+
+// Stop gracefully stops the depth stream.
+func (bs *BookStreamer) Stop() {
+	if bs.isRunning {
+		close(bs.stopChan)
+		bs.isRunning = false
+		log.Println("🛑 Stopped depth stream")
+	}
+}
+
+// GetUpdateChannel returns the channel of raw depth-diff events.
+func (bs *BookStreamer) GetUpdateChannel() <-chan DepthDiffEvent {
+	return bs.updateChan
+}
+
+// GetErrorChannel returns the channel for errors.
+func (bs *BookStreamer) GetErrorChannel() <-chan error {
+	return bs.errorChan
+}
+
+// Symbols returns the set of symbols currently being watched.
+func (bs *BookStreamer) Symbols() []string {
+	bs.symbolsMu.Lock()
+	defer bs.symbolsMu.Unlock()
+
+	out := make([]string, 0, len(bs.symbols))
+	for s := range bs.symbols {
+		out = append(out, s)
+	}
+	return out
+}
+
+// AddSymbol starts watching a new symbol's order book.
+func (bs *BookStreamer) AddSymbol(symbol string) error {
+	symbol = strings.ToUpper(symbol)
+
+	bs.symbolsMu.Lock()
+	if bs.symbols[symbol] {
+		bs.symbolsMu.Unlock()
+		return nil
+	}
+	bs.symbols[symbol] = true
+	bs.symbolsMu.Unlock()
+
+	return bs.sendControl("SUBSCRIBE", []string{depthStreamName(symbol)})
+}
+
+// RemoveSymbol stops watching a symbol's order book.
+func (bs *BookStreamer) RemoveSymbol(symbol string) error {
+	symbol = strings.ToUpper(symbol)
+
+	bs.symbolsMu.Lock()
+	if !bs.symbols[symbol] {
+		bs.symbolsMu.Unlock()
+		return nil
+	}
+	delete(bs.symbols, symbol)
+	bs.symbolsMu.Unlock()
+
+	return bs.sendControl("UNSUBSCRIBE", []string{depthStreamName(symbol)})
+}
+
+func depthStreamName(symbol string) string {
+	return strings.ToLower(symbol) + "@depth@100ms"
+}
+
+func (bs *BookStreamer) sendControl(method string, params []string) error {
+	bs.connMu.Lock()
+	defer bs.connMu.Unlock()
+
+	if bs.conn == nil {
+		return nil
+	}
+
+	return bs.conn.WriteJSON(streamControlRequest{
+		Method: method,
+		Params: params,
+		ID:     atomic.AddInt64(&bs.requestID, 1),
+	})
+}
+
+func (bs *BookStreamer) startStream() {
+	for {
+		select {
+		case <-bs.stopChan:
+			return
+		default:
+			bs.connectAndListen()
+			time.Sleep(5 * time.Second)
+			log.Println("♻️  Attempting to reconnect depth stream...")
+		}
+	}
+}
+
+func (bs *BookStreamer) connectAndListen() {
+	symbols := bs.Symbols()
+	if len(symbols) == 0 {
+		time.Sleep(time.Second)
+		return
+	}
+
+	streams := make([]string, len(symbols))
+	for i, s := range symbols {
+		streams[i] = depthStreamName(s)
+	}
+	url := fmt.Sprintf("%s?streams=%s", combinedStreamURL, strings.Join(streams, "/"))
+
+	conn, _, err := ws.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		log.Printf("❌ Failed to dial combined depth stream: %v", err)
+		bs.errorChan <- err
+		return
+	}
+
+	bs.connMu.Lock()
+	bs.conn = conn
+	bs.connMu.Unlock()
+
+	defer func() {
+		bs.connMu.Lock()
+		bs.conn = nil
+		bs.connMu.Unlock()
+		conn.Close()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				log.Printf("⚠️  Depth stream read error: %v", err)
+				return
+			}
+
+			var envelope combinedStreamEnvelope
+			if err := json.Unmarshal(message, &envelope); err != nil {
+				continue
+			}
+
+			var raw rawDepthEvent
+			if err := json.Unmarshal(envelope.Data, &raw); err != nil {
+				continue
+			}
+			if raw.EventType != "depthUpdate" {
+				continue
+			}
+
+			update := DepthDiffEvent{
+				Symbol:        raw.Symbol,
+				FirstUpdateID: raw.FirstUpdateID,
+				FinalUpdateID: raw.FinalUpdateID,
+				Bids:          raw.Bids,
+				Asks:          raw.Asks,
+			}
+
+			select {
+			case bs.updateChan <- update:
+			default:
+				log.Printf("⚠️  Depth update channel full, dropping update for %s", update.Symbol)
+			}
+		}
+	}()
+
+	select {
+	case <-bs.stopChan:
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return
+	case <-done:
+		log.Println("⚠️  Combined depth stream connection closed")
+		return
+	}
+}
@@ -1,55 +1,200 @@
 package websocket
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"fmt"
 	"log"
+	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	ws "github.com/gorilla/websocket"
 )
 
+const (
+	// clientWriteWait bounds how long a single WriteMessage (including the
+	// coalesced-frame writes in writePump) may block before the client is
+	// considered unresponsive.
+	clientWriteWait = 10 * time.Second
+
+	// clientPongWait is both the read deadline renewed by the pong handler
+	// and the window a ping is given to be answered before it counts as
+	// missed.
+	clientPongWait = 60 * time.Second
+
+	// clientPingPeriod must be shorter than clientPongWait so at least one
+	// ping always lands inside the current deadline.
+	clientPingPeriod = (clientPongWait * 9) / 10
+
+	// maxMissedPongs is how many consecutive unanswered pings writePump
+	// tolerates before evicting the client as a slow/dead consumer.
+	maxMissedPongs = 3
+
+	defaultSendBufferSize = 256
+	defaultMaxMessageSize = 1 << 20 // 1MiB
+)
+
 // BroadcastHub manages multiple WebSocket connections and broadcasts data efficiently
 // This replaces the simple Hub pattern with a more robust solution
 type BroadcastHub struct {
-	clients      map[*ws.Conn]*ClientConnection
-	register     chan *ClientConnection
-	unregister   chan *ClientConnection
-	broadcast    chan []byte
-	mu           sync.RWMutex
-	isRunning    bool
+	clients    map[*ws.Conn]*ClientConnection
+	register   chan *ClientConnection
+	unregister chan *ClientConnection
+	broadcast  chan []byte
+	mu         sync.RWMutex
+	isRunning  bool
+
+	// streams holds one ExchangeStream per registered venue name, so a
+	// client can be pointed at a venue other than Binance without the hub
+	// itself needing to know anything about that venue's wire protocol.
+	streams   map[string]ExchangeStream
+	streamsMu sync.RWMutex
 }
 
 // ClientConnection represents a connected client with metadata
 type ClientConnection struct {
-	conn     *ws.Conn
-	Symbol   string    // Exported fields for external access
-	Interval string    // Exported fields for external access
-	send     chan []byte
-	mu       sync.Mutex
+	conn      *ws.Conn
+	Symbol    string   // Exported fields for external access
+	Interval  string   // Exported fields for external access
+	Exchange  string   // Venue name (e.g. "binance", "ftx"); defaults to "binance"
+	Encoding  Encoding // Wire format negotiated at registration; defaults to EncodingJSON
+	send      chan []byte
+	mu        sync.Mutex
+	closeOnce sync.Once
+	closed    bool // Guarded by mu; set by Close so enqueue never sends on a closed send channel
+
+	// topics is the multiplexed subscription set used by /api/stream
+	// clients, independent of the single Symbol/Interval pair every other
+	// WS endpoint still registers with. A client can hold both at once;
+	// BroadcastToSymbol and BroadcastToTopic deliver to whichever matches.
+	topics   map[string]bool
+	topicsMu sync.Mutex
+
+	// maxMessageSize is applied to the connection via conn.SetReadLimit at
+	// registration time, from the ClientOptions the client was registered
+	// with.
+	maxMessageSize int64
+
+	// Backpressure/liveness bookkeeping, surfaced via BroadcastHub.Stats().
+	// All four are written from multiple goroutines (writePump, the pong
+	// handler, enqueue) so they're accessed exclusively through sync/atomic.
+	bytesSent       uint64
+	droppedMessages uint64
+	lastPongUnix    int64
+	missedPongs     int32
+}
+
+// enqueue queues data for delivery to the client, dropping it (and counting
+// it in droppedMessages, visible via BroadcastHub.Stats) if the client's send
+// buffer is full rather than blocking the broadcaster on one slow consumer.
+// Checking closed under the same lock Close sets it under is what stops this
+// from racing Close's close(c.send) and sending on a closed channel.
+func (c *ClientConnection) enqueue(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		atomic.AddUint64(&c.droppedMessages, 1)
+		return false
+	}
+
+	select {
+	case c.send <- data:
+		return true
+	default:
+		atomic.AddUint64(&c.droppedMessages, 1)
+		return false
+	}
+}
+
+// wsMessageType is the WS frame opcode for payloads already encoded for this
+// client's Encoding: ws.TextMessage for JSON (the default), ws.BinaryMessage
+// for everything else.
+func (c *ClientConnection) wsMessageType() int {
+	if c.Encoding == "" || c.Encoding == EncodingJSON {
+		return ws.TextMessage
+	}
+	return ws.BinaryMessage
+}
+
+// Subscribe adds topic (e.g. "kline:BTCUSDT:1m", "price:ETHUSDT") to the
+// client's multiplexed subscription set.
+func (c *ClientConnection) Subscribe(topic string) {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+	if c.topics == nil {
+		c.topics = make(map[string]bool)
+	}
+	c.topics[topic] = true
+}
+
+// Unsubscribe removes topic from the client's multiplexed subscription set.
+func (c *ClientConnection) Unsubscribe(topic string) {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+	delete(c.topics, topic)
+}
+
+// HasTopic reports whether the client is currently subscribed to topic.
+func (c *ClientConnection) HasTopic(topic string) bool {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+	return c.topics[topic]
+}
+
+// Topics returns the client's current multiplexed subscription set.
+func (c *ClientConnection) Topics() []string {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+
+	out := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		out = append(out, topic)
+	}
+	return out
 }
 
 // NewBroadcastHub creates a new broadcast hub
 func NewBroadcastHub() *BroadcastHub {
 	return &BroadcastHub{
-		clients:   make(map[*ws.Conn]*ClientConnection),
-		register:  make(chan *ClientConnection),
+		clients:    make(map[*ws.Conn]*ClientConnection),
+		register:   make(chan *ClientConnection),
 		unregister: make(chan *ClientConnection),
-		broadcast: make(chan []byte, 256), // Buffered channel
+		broadcast:  make(chan []byte, 256), // Buffered channel
+		streams:    make(map[string]ExchangeStream),
 	}
 }
 
+// RegisterStream adds or replaces the ExchangeStream dispatched to for
+// venue name (see ClientConnection.Exchange).
+func (h *BroadcastHub) RegisterStream(name string, stream ExchangeStream) {
+	h.streamsMu.Lock()
+	defer h.streamsMu.Unlock()
+	h.streams[name] = stream
+}
+
+// GetStream resolves a registered ExchangeStream by venue name.
+func (h *BroadcastHub) GetStream(name string) (ExchangeStream, bool) {
+	h.streamsMu.RLock()
+	defer h.streamsMu.RUnlock()
+	stream, ok := h.streams[name]
+	return stream, ok
+}
+
 // Run starts the broadcast hub
 func (h *BroadcastHub) Run() {
 	h.isRunning = true
-	
+
 	for h.isRunning {
 		select {
 		case client := <-h.register:
 			h.registerClient(client)
-			
+
 		case client := <-h.unregister:
 			h.unregisterClient(client)
-			
+
 		case message := <-h.broadcast:
 			h.broadcastMessage(message)
 		}
@@ -59,7 +204,7 @@ func (h *BroadcastHub) Run() {
 // Stop stops the broadcast hub
 func (h *BroadcastHub) Stop() {
 	h.isRunning = false
-	
+
 	// Close all client connections
 	h.mu.Lock()
 	for _, client := range h.clients {
@@ -67,23 +212,120 @@ func (h *BroadcastHub) Stop() {
 	}
 	h.clients = make(map[*ws.Conn]*ClientConnection)
 	h.mu.Unlock()
-	
+
 	log.Println("🛑 Broadcast hub stopped")
 }
 
-// RegisterClient adds a new client to the hub
+// ClientOptions configures per-client backpressure limits. Zero fields are
+// filled in by RegisterClientWithOptions: the sizes with the
+// DefaultClientOptions values, Encoding with EncodingJSON.
+type ClientOptions struct {
+	// SendBufferSize is the capacity of the client's outgoing queue. Once
+	// full, further broadcasts to this client are dropped (counted in
+	// ClientStats.DroppedMessages) instead of blocking the broadcaster.
+	SendBufferSize int
+	// MaxMessageSize caps incoming frame size via conn.SetReadLimit; the
+	// connection is closed if a client exceeds it.
+	MaxMessageSize int64
+	// Encoding is the wire format this client's frames are marshaled with
+	// (see Encoding). Anything other than EncodingJSON also turns on
+	// permessage-deflate write compression on the connection, since binary payloads still
+	// compress well and the hub may be fanning out sub-second updates.
+	Encoding Encoding
+}
+
+// DefaultClientOptions returns the options RegisterClient uses.
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{SendBufferSize: defaultSendBufferSize, MaxMessageSize: defaultMaxMessageSize}
+}
+
+// RegisterClient adds a new client to the hub with DefaultClientOptions,
+// defaulting Exchange to "binance" (see ClientConnection.Exchange); callers
+// that want another venue set client.Exchange themselves right after this
+// returns.
 func (h *BroadcastHub) RegisterClient(conn *ws.Conn, symbol, interval string) *ClientConnection {
+	return h.RegisterClientWithOptions(conn, symbol, interval, DefaultClientOptions())
+}
+
+// RegisterClientWithOptions is RegisterClient with caller-chosen backpressure
+// limits (see ClientOptions). It also arms the connection's read side for
+// writePump's ping/pong liveness check: a read limit, an initial read
+// deadline, and a pong handler that renews the deadline and clears
+// missedPongs. This must happen before the caller's own read loop starts, so
+// it's done synchronously here rather than inside the hub's register
+// goroutine.
+func (h *BroadcastHub) RegisterClientWithOptions(conn *ws.Conn, symbol, interval string, opts ClientOptions) *ClientConnection {
+	if opts.SendBufferSize <= 0 {
+		opts.SendBufferSize = defaultSendBufferSize
+	}
+	if opts.MaxMessageSize <= 0 {
+		opts.MaxMessageSize = defaultMaxMessageSize
+	}
+	if opts.Encoding == "" {
+		opts.Encoding = EncodingJSON
+	}
+
 	client := &ClientConnection{
-		conn:     conn,
-		Symbol:   symbol,   // Use exported fields
-		Interval: interval, // Use exported fields
-		send:     make(chan []byte, 256),
+		conn:           conn,
+		Symbol:         symbol,   // Use exported fields
+		Interval:       interval, // Use exported fields
+		Exchange:       "binance",
+		Encoding:       opts.Encoding,
+		send:           make(chan []byte, opts.SendBufferSize),
+		maxMessageSize: opts.MaxMessageSize,
 	}
-	
+
+	if opts.Encoding != EncodingJSON {
+		// Binary payloads still benefit from permessage-deflate (negotiated
+		// at Upgrade time via the server's ws.Upgrader.EnableCompression);
+		// this just turns on write-side compression for this connection.
+		conn.EnableWriteCompression(true)
+		conn.SetCompressionLevel(gzip.DefaultCompression)
+	}
+
+	conn.SetReadLimit(opts.MaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(clientPongWait))
+	conn.SetPongHandler(func(string) error {
+		atomic.StoreInt64(&client.lastPongUnix, time.Now().Unix())
+		atomic.StoreInt32(&client.missedPongs, 0)
+		return conn.SetReadDeadline(time.Now().Add(clientPongWait))
+	})
+
 	h.register <- client
 	return client
 }
 
+// ClientStats is one client's snapshot of backpressure/liveness counters, as
+// returned by BroadcastHub.Stats().
+type ClientStats struct {
+	Symbol          string
+	Interval        string
+	Exchange        string
+	BytesSent       uint64
+	DroppedMessages uint64
+	LastPongUnix    int64 // Unix seconds; 0 if no pong has been received yet.
+}
+
+// Stats returns a point-in-time snapshot of every connected client's
+// backpressure/liveness counters.
+func (h *BroadcastHub) Stats() []ClientStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := make([]ClientStats, 0, len(h.clients))
+	for _, client := range h.clients {
+		stats = append(stats, ClientStats{
+			Symbol:          client.Symbol,
+			Interval:        client.Interval,
+			Exchange:        client.Exchange,
+			BytesSent:       atomic.LoadUint64(&client.bytesSent),
+			DroppedMessages: atomic.LoadUint64(&client.droppedMessages),
+			LastPongUnix:    atomic.LoadInt64(&client.lastPongUnix),
+		})
+	}
+	return stats
+}
+
 // UnregisterClient removes a client from the hub
 func (h *BroadcastHub) UnregisterClient(client *ClientConnection) {
 	h.unregister <- client
@@ -100,7 +342,7 @@ func (h *BroadcastHub) GetClientCount() int {
 func (h *BroadcastHub) GetClientsBySymbol(symbol string) []*ClientConnection {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	
+
 	targetClients := make([]*ClientConnection, 0)
 	for _, client := range h.clients {
 		if client.Symbol == symbol { // Use exported field
@@ -117,7 +359,7 @@ func (h *BroadcastHub) BroadcastToAll(data interface{}) {
 		log.Printf("❌ Failed to marshal broadcast data: %v", err)
 		return
 	}
-	
+
 	select {
 	case h.broadcast <- jsonData:
 	default:
@@ -125,27 +367,102 @@ func (h *BroadcastHub) BroadcastToAll(data interface{}) {
 	}
 }
 
+// deliver encodes data once per distinct Encoding in use among clients
+// (see encodeOnce) and enqueues the right bytes to each, instead of
+// marshaling once as JSON and writing that to every client regardless of
+// what wire format it negotiated.
+func (h *BroadcastHub) deliver(clients []*ClientConnection, data interface{}) {
+	cache := make(map[Encoding][]byte, 2)
+	for _, client := range clients {
+		payload, err := encodeOnce(cache, client.Encoding, data)
+		if err != nil {
+			log.Printf("❌ Failed to encode broadcast data (%s): %v", client.Encoding, err)
+			continue
+		}
+		if !client.enqueue(payload) {
+			log.Printf("⚠️  Client send channel full")
+		}
+	}
+}
+
 // BroadcastToSymbol sends message to clients subscribed to a specific symbol
 func (h *BroadcastHub) BroadcastToSymbol(symbol string, data interface{}) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("❌ Failed to marshal symbol broadcast data: %v", err)
-		return
+	h.deliver(h.GetClientsBySymbol(symbol), data)
+}
+
+// BroadcastToSymbolOnExchange sends message to clients subscribed to symbol
+// on exchange specifically, unlike BroadcastToSymbol which ignores Exchange
+// entirely (kept for the existing Binance-only call sites). Use this once a
+// second venue's updates need routing to just the clients that asked for it.
+func (h *BroadcastHub) BroadcastToSymbolOnExchange(exchange, symbol string, data interface{}) {
+	h.mu.RLock()
+	targetClients := make([]*ClientConnection, 0)
+	for _, client := range h.clients {
+		if client.Symbol == symbol && client.Exchange == exchange {
+			targetClients = append(targetClients, client)
+		}
 	}
-	
-	targetClients := h.GetClientsBySymbol(symbol)
-	for _, client := range targetClients {
-		select {
-		case client.send <- jsonData:
-		default:
-			log.Printf("⚠️  Client send channel full for %s", symbol)
+	h.mu.RUnlock()
+
+	h.deliver(targetClients, data)
+}
+
+// BroadcastToTopic sends message to every client whose multiplexed
+// subscription set (see ClientConnection.Subscribe) includes topic. It is
+// independent of BroadcastToSymbol's Symbol-field matching, so the same
+// upstream event can be routed to both legacy single-purpose clients and
+// /api/stream clients without either seeing duplicates they didn't ask for.
+func (h *BroadcastHub) BroadcastToTopic(topic string, data interface{}) {
+	h.mu.RLock()
+	targetClients := make([]*ClientConnection, 0)
+	for _, client := range h.clients {
+		if client.HasTopic(topic) {
+			targetClients = append(targetClients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	h.deliver(targetClients, data)
+}
+
+// BroadcastBinary is deliver's forced-binary counterpart: data is encoded
+// once per binary Encoding in use (MessagePack, falling back from
+// EncodingProtobufGzip — see encodePayload) and fanned out as
+// ws.BinaryMessage frames to every client that negotiated a non-JSON
+// Encoding at registration. Clients still on EncodingJSON are skipped; use
+// BroadcastToSymbol/BroadcastToTopic for those instead, which is the usual
+// shape since most callers want both groups covered for the same event.
+func (h *BroadcastHub) BroadcastBinary(data interface{}) {
+	h.mu.RLock()
+	binaryClients := make([]*ClientConnection, 0)
+	for _, client := range h.clients {
+		if client.Encoding != "" && client.Encoding != EncodingJSON {
+			binaryClients = append(binaryClients, client)
 		}
 	}
+	h.mu.RUnlock()
+
+	h.deliver(binaryClients, data)
+}
+
+// SendToClient sends an arbitrary payload to a single client, used by
+// /api/stream to reply to SUBSCRIBE/UNSUBSCRIBE/LIST_SUBSCRIPTIONS control
+// messages.
+func (h *BroadcastHub) SendToClient(client *ClientConnection, data interface{}) error {
+	payload, err := encodePayload(client.Encoding, data)
+	if err != nil {
+		return err
+	}
+
+	if !client.enqueue(payload) {
+		return fmt.Errorf("client send channel full")
+	}
+	return nil
 }
 
 // SendSnapshot sends buffered data to a specific client
 func (h *BroadcastHub) SendSnapshot(client *ClientConnection, snapshot map[string]interface{}) {
-	jsonData, err := json.Marshal(map[string]interface{}{
+	payload, err := encodePayload(client.Encoding, map[string]interface{}{
 		"type":     "snapshot",
 		"data":     snapshot,
 		"symbol":   client.Symbol,   // Use exported field
@@ -155,25 +472,43 @@ func (h *BroadcastHub) SendSnapshot(client *ClientConnection, snapshot map[strin
 		log.Printf("❌ Failed to marshal snapshot: %v", err)
 		return
 	}
-	
-	select {
-	case client.send <- jsonData:
+
+	if client.enqueue(payload) {
 		log.Printf("📸 Sent snapshot to client for %s", client.Symbol) // Use exported field
-	default:
+	} else {
 		log.Printf("⚠️  Failed to send snapshot - channel full")
 	}
 }
 
+// DropRandomClient force-disconnects one randomly chosen connected client.
+// Intended only for the --chaos test harness, to exercise client-side
+// reconnect logic under CI integration tests.
+func (h *BroadcastHub) DropRandomClient() {
+	h.mu.RLock()
+	clients := make([]*ClientConnection, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	if len(clients) == 0 {
+		return
+	}
+
+	victim := clients[rand.Intn(len(clients))]
+	go h.unregisterClient(victim)
+}
+
 // --- Internal Methods ---
 
 func (h *BroadcastHub) registerClient(client *ClientConnection) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	
+
 	h.clients[client.conn] = client
-	log.Printf("👋 Client connected: %s (%s) | Total: %d", 
+	log.Printf("👋 Client connected: %s (%s) | Total: %d",
 		client.Symbol, client.Interval, len(h.clients)) // Use exported fields
-	
+
 	// Start client writer goroutine
 	go h.writePump(client)
 }
@@ -181,11 +516,11 @@ func (h *BroadcastHub) registerClient(client *ClientConnection) {
 func (h *BroadcastHub) unregisterClient(client *ClientConnection) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	
+
 	if _, ok := h.clients[client.conn]; ok {
 		delete(h.clients, client.conn)
 		client.Close()
-		log.Printf("👋 Client disconnected: %s | Total: %d", 
+		log.Printf("👋 Client disconnected: %s | Total: %d",
 			client.Symbol, len(h.clients)) // Use exported field
 	}
 }
@@ -193,50 +528,118 @@ func (h *BroadcastHub) unregisterClient(client *ClientConnection) {
 func (h *BroadcastHub) broadcastMessage(message []byte) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	
+
 	for _, client := range h.clients {
-		select {
-		case client.send <- message:
-		default:
-			// Client send channel is full, close and remove
-			log.Printf("⚠️  Client send channel full, disconnecting")
-			go h.unregisterClient(client)
+		if !client.enqueue(message) {
+			// Slow consumer: writePump's own ping/pong check will evict it
+			// if this persists, so just count the drop and move on instead
+			// of racing unregisterClient against every other broadcaster.
+			log.Printf("⚠️  Client send channel full, dropping message")
 		}
 	}
 }
 
+// writePump owns the client's connection for writes: it drains client.send,
+// coalescing whatever else is already queued into the same WS frame, and
+// drives a ping/pong liveness check that evicts the client after
+// maxMissedPongs consecutive unanswered pings. It's the only goroutine
+// allowed to write to client.conn (gorilla permits one concurrent reader and
+// one concurrent writer; the reader is whichever handler registered the
+// client).
 func (h *BroadcastHub) writePump(client *ClientConnection) {
-	defer client.Close()
-	
+	ticker := time.NewTicker(clientPingPeriod)
+	defer func() {
+		ticker.Stop()
+		client.Close()
+	}()
+
 	for {
 		select {
 		case message, ok := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(clientWriteWait))
 			if !ok {
 				// Hub closed the channel
+				client.conn.WriteMessage(ws.CloseMessage, []byte{})
 				return
 			}
-			
-			client.mu.Lock()
-			err := client.conn.WriteMessage(ws.TextMessage, message)
-			client.mu.Unlock()
-			
+
+			msgType := client.wsMessageType()
+			w, err := client.conn.NextWriter(msgType)
 			if err != nil {
 				log.Printf("❌ Write error: %v", err)
 				h.unregisterClient(client)
 				return
 			}
+			n, _ := w.Write(message)
+			atomic.AddUint64(&client.bytesSent, uint64(n))
+
+			// Coalesce whatever else is already queued into this same frame
+			// instead of one WriteMessage syscall per update — but only for
+			// JSON clients. msgpack (and any other binary encoding) has no
+			// delimiter-based framing, so joining payloads with '\n' would
+			// hand the client an undecodable blob; those get one WS frame
+			// per queued message below instead.
+			pending := len(client.send)
+			if msgType == ws.TextMessage {
+				for i := 0; i < pending; i++ {
+					extra := <-client.send
+					w.Write([]byte{'\n'})
+					n, _ := w.Write(extra)
+					atomic.AddUint64(&client.bytesSent, uint64(n))
+				}
+				pending = 0
+			}
+
+			if err := w.Close(); err != nil {
+				log.Printf("❌ Write error: %v", err)
+				h.unregisterClient(client)
+				return
+			}
+
+			for i := 0; i < pending; i++ {
+				extra := <-client.send
+				client.conn.SetWriteDeadline(time.Now().Add(clientWriteWait))
+				if err := client.conn.WriteMessage(msgType, extra); err != nil {
+					log.Printf("❌ Write error: %v", err)
+					h.unregisterClient(client)
+					return
+				}
+				atomic.AddUint64(&client.bytesSent, uint64(len(extra)))
+			}
+
+		case <-ticker.C:
+			if atomic.LoadInt32(&client.missedPongs) >= maxMissedPongs {
+				log.Printf("☠️  Evicting slow/dead client %s: missed %d consecutive pongs", client.Symbol, maxMissedPongs)
+				h.unregisterClient(client)
+				return
+			}
+
+			client.conn.SetWriteDeadline(time.Now().Add(clientWriteWait))
+			if err := client.conn.WriteMessage(ws.PingMessage, nil); err != nil {
+				log.Printf("❌ Ping error: %v", err)
+				h.unregisterClient(client)
+				return
+			}
+			// Assumed missed until the pong handler (armed at registration)
+			// clears it back to 0 on reply.
+			atomic.AddInt32(&client.missedPongs, 1)
 		}
 	}
 }
 
-// Close closes the client connection
+// Close closes the client connection. Safe to call more than once (writePump
+// calls it via unregisterClient on every return path, then again via its own
+// deferred cleanup) — only the first call actually closes conn/send.
 func (c *ClientConnection) Close() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	
-	if c.conn != nil {
-		c.conn.Close()
-	}
-	
-	close(c.send)
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.closed = true
+		if c.conn != nil {
+			c.conn.Close()
+		}
+
+		close(c.send)
+	})
 }
@@ -17,13 +17,18 @@ type DataBuffer struct {
 	mu          sync.RWMutex
 }
 
-// NewDataBuffer creates a new data buffer for a symbol
-func NewDataBuffer(symbol, interval string) *DataBuffer {
+// NewDataBuffer creates a new data buffer for a symbol, keeping up to
+// maxHistory candles (defaulting to 1000 when <= 0).
+func NewDataBuffer(symbol, interval string, maxHistory int) *DataBuffer {
+	if maxHistory <= 0 {
+		maxHistory = 1000
+	}
+
 	return &DataBuffer{
-		symbol:     symbol,
-		interval:   interval,
-		maxHistory: 1000,
-		klineHistory: make([]KlineUpdate, 0, 1000),
+		symbol:       symbol,
+		interval:     interval,
+		maxHistory:   maxHistory,
+		klineHistory: make([]KlineUpdate, 0, maxHistory),
 	}
 }
 
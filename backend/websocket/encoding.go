@@ -0,0 +1,76 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoding selects the wire format BroadcastHub uses for a client's frames,
+// negotiated per connection via a ?encoding= query param (see
+// ParseEncoding). Binary encodings are paired with permessage-deflate (see
+// RegisterClientWithOptions) since MessagePack payloads compress just as
+// well as JSON once the repeated field names are gone — worthwhile once the
+// hub is fanning sub-second trade/kline updates out to many clients.
+type Encoding string
+
+const (
+	EncodingJSON Encoding = "json"
+
+	// EncodingMsgpack marshals with MessagePack instead of encoding/json,
+	// trimming the per-frame overhead of repeating field names across a
+	// high-frequency stream.
+	EncodingMsgpack Encoding = "msgpack"
+
+	// EncodingProtobufGzip is accepted but isn't backed by real .proto
+	// message definitions for KlineUpdate/PriceUpdate/DepthDiffEvent yet;
+	// until those exist it falls back to EncodingMsgpack so a client that
+	// asks for it still gets a binary, compression-friendly frame instead
+	// of an error.
+	EncodingProtobufGzip Encoding = "protobuf+gzip"
+)
+
+// ParseEncoding maps a ?encoding= query value to an Encoding, defaulting to
+// EncodingJSON for "" or anything unrecognized so clients that don't send
+// the param keep working exactly as before.
+func ParseEncoding(raw string) Encoding {
+	switch Encoding(raw) {
+	case EncodingMsgpack:
+		return EncodingMsgpack
+	case EncodingProtobufGzip:
+		return EncodingProtobufGzip
+	default:
+		return EncodingJSON
+	}
+}
+
+// encodePayload marshals data per enc. Everything other than EncodingJSON
+// currently goes through MessagePack (see EncodingProtobufGzip).
+func encodePayload(enc Encoding, data interface{}) ([]byte, error) {
+	switch enc {
+	case EncodingMsgpack, EncodingProtobufGzip:
+		b, err := msgpack.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("msgpack marshal: %w", err)
+		}
+		return b, nil
+	default:
+		return json.Marshal(data)
+	}
+}
+
+// encodeOnce is encodePayload memoized per Encoding in cache, so a broadcast
+// to many clients marshals once per distinct Encoding in use rather than
+// once per client.
+func encodeOnce(cache map[Encoding][]byte, enc Encoding, data interface{}) ([]byte, error) {
+	if b, ok := cache[enc]; ok {
+		return b, nil
+	}
+	b, err := encodePayload(enc, data)
+	if err != nil {
+		return nil, err
+	}
+	cache[enc] = b
+	return b, nil
+}
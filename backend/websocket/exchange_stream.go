@@ -0,0 +1,146 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+)
+
+// ExchangeStream is the pluggable streaming surface, implemented once per
+// venue (binance today; FTX-style and KuCoin-style stubs alongside it). It
+// mirrors the exchange package's per-venue REST surface, but for live
+// trade/kline/depth feeds rather than request/response calls. Connect
+// performs whatever handshake the venue needs before Subscribe can
+// succeed — KuCoin needs a REST "bullet" token first, FTX needs a login
+// frame sent over the socket before anything else, Binance needs neither.
+type ExchangeStream interface {
+	// Name identifies the adapter, matching the venue name it should be
+	// registered under in BroadcastHub.RegisterStream.
+	Name() string
+
+	// Connect performs whatever the venue needs before Subscribe can
+	// succeed. BinanceStream's Connect dials nothing: it starts forwarding
+	// its wrapped streamers' updates and returns at once.
+	Connect(ctx context.Context) error
+
+	Subscribe(symbol, interval string) error
+	Unsubscribe(symbol, interval string) error
+
+	OnKline(fn func(KlineUpdate))
+	OnTrade(fn func(PriceUpdate))
+	OnDepth(fn func(DepthDiffEvent))
+}
+
+// StandardStream is embedded by every ExchangeStream implementation. It
+// centralizes callback registration and the active subscription set, so an
+// adapter whose live connection drops has a ready-made record of what to
+// replay on reconnect, instead of reinventing that bookkeeping per venue.
+type StandardStream struct {
+	mu            sync.Mutex
+	subscriptions map[string]bool
+
+	klineHandlers []func(KlineUpdate)
+	tradeHandlers []func(PriceUpdate)
+	depthHandlers []func(DepthDiffEvent)
+}
+
+// OnKline registers fn to be called with every kline update the stream
+// emits via EmitKline.
+func (s *StandardStream) OnKline(fn func(KlineUpdate)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.klineHandlers = append(s.klineHandlers, fn)
+}
+
+// OnTrade registers fn to be called with every trade update the stream
+// emits via EmitTrade.
+func (s *StandardStream) OnTrade(fn func(PriceUpdate)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tradeHandlers = append(s.tradeHandlers, fn)
+}
+
+// OnDepth registers fn to be called with every depth diff the stream emits
+// via EmitDepth.
+func (s *StandardStream) OnDepth(fn func(DepthDiffEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.depthHandlers = append(s.depthHandlers, fn)
+}
+
+// EmitKline fans update out to every handler registered via OnKline.
+func (s *StandardStream) EmitKline(update KlineUpdate) {
+	for _, fn := range s.klineSnapshot() {
+		fn(update)
+	}
+}
+
+// EmitTrade fans update out to every handler registered via OnTrade.
+func (s *StandardStream) EmitTrade(update PriceUpdate) {
+	for _, fn := range s.tradeSnapshot() {
+		fn(update)
+	}
+}
+
+// EmitDepth fans update out to every handler registered via OnDepth.
+func (s *StandardStream) EmitDepth(update DepthDiffEvent) {
+	for _, fn := range s.depthSnapshot() {
+		fn(update)
+	}
+}
+
+func (s *StandardStream) klineSnapshot() []func(KlineUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]func(KlineUpdate){}, s.klineHandlers...)
+}
+
+func (s *StandardStream) tradeSnapshot() []func(PriceUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]func(PriceUpdate){}, s.tradeHandlers...)
+}
+
+func (s *StandardStream) depthSnapshot() []func(DepthDiffEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]func(DepthDiffEvent){}, s.depthHandlers...)
+}
+
+// addSubscription records symbol/interval as actively subscribed, so a
+// reconnecting adapter can call Subscriptions() to replay them.
+func (s *StandardStream) addSubscription(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[string]bool)
+	}
+	s.subscriptions[key] = true
+}
+
+func (s *StandardStream) removeSubscription(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscriptions, key)
+}
+
+// Subscriptions returns every currently active "<symbol>" (trade/depth-only)
+// or "<symbol>_<interval>" (kline) key, for replay after a reconnect.
+func (s *StandardStream) Subscriptions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]string, 0, len(s.subscriptions))
+	for key := range s.subscriptions {
+		out = append(out, key)
+	}
+	return out
+}
+
+// subscriptionKey builds the key addSubscription/removeSubscription track
+// a symbol (and, for klines, interval) under.
+func subscriptionKey(symbol, interval string) string {
+	if interval == "" {
+		return symbol
+	}
+	return symbol + "_" + interval
+}
@@ -0,0 +1,62 @@
+package websocket
+
+import "math"
+
+// HeikinAshiCandle holds the four OHLC values the Heikin-Ashi recurrence
+// operates on, independent of whichever concrete kline type the caller has.
+type HeikinAshiCandle struct {
+	Open, High, Low, Close float64
+}
+
+// ToHeikinAshi converts a chronologically-ordered slice of raw candles into
+// Heikin-Ashi candles: haClose is the OHLC4 average, haOpen is the midpoint
+// of the previous Heikin-Ashi candle (seeded from the first raw candle's
+// open/close), and haHigh/haLow widen the raw high/low to also cover
+// haOpen/haClose.
+func ToHeikinAshi(candles []HeikinAshiCandle) []HeikinAshiCandle {
+	out := make([]HeikinAshiCandle, len(candles))
+	var prevOpen, prevClose float64
+
+	for i, c := range candles {
+		haClose := (c.Open + c.High + c.Low + c.Close) / 4
+
+		var haOpen float64
+		if i == 0 {
+			haOpen = (c.Open + c.Close) / 2
+		} else {
+			haOpen = (prevOpen + prevClose) / 2
+		}
+
+		out[i] = HeikinAshiCandle{
+			Open:  haOpen,
+			High:  math.Max(c.High, math.Max(haOpen, haClose)),
+			Low:   math.Min(c.Low, math.Min(haOpen, haClose)),
+			Close: haClose,
+		}
+
+		prevOpen, prevClose = haOpen, haClose
+	}
+
+	return out
+}
+
+// ToHeikinAshiUpdates transforms a chronologically-ordered slice of
+// KlineUpdate into Heikin-Ashi candles, preserving every other field.
+func ToHeikinAshiUpdates(updates []KlineUpdate) []KlineUpdate {
+	candles := make([]HeikinAshiCandle, len(updates))
+	for i, u := range updates {
+		candles[i] = HeikinAshiCandle{Open: u.Open, High: u.High, Low: u.Low, Close: u.Close}
+	}
+
+	ha := ToHeikinAshi(candles)
+
+	out := make([]KlineUpdate, len(updates))
+	for i, u := range updates {
+		out[i] = u
+		out[i].Open = ha[i].Open
+		out[i].High = ha[i].High
+		out[i].Low = ha[i].Low
+		out[i].Close = ha[i].Close
+	}
+	return out
+}
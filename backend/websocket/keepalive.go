@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+)
+
+// readDeadline is how long a connection may go without a data or ping
+// frame before it's considered dead and a reconnect is forced.
+const readDeadline = 60 * time.Second
+
+// pongInterval is how often an unsolicited PONG frame is sent as an extra
+// keepalive on top of replying to the server's own PINGs, matching
+// Binance's documented "send an unsolicited pong every 3 minutes" advice.
+const pongInterval = 3 * time.Minute
+
+// streamDialer is the shared Dialer used for every direct (non-SDK) stream
+// connection, tuned generously above Binance's own limits so slow
+// handshakes and large combined-stream frames don't spuriously fail.
+var streamDialer = &ws.Dialer{
+	HandshakeTimeout: 45 * time.Second,
+	ReadBufferSize:   8192,
+}
+
+// armKeepalive wires up conn's initial read deadline and PING handler (which
+// both replies with PONG and renews the deadline), then starts a goroutine
+// that sends an unsolicited PONG every pongInterval until connCtx is
+// cancelled. Call once per connection, right after a successful dial; the
+// caller is responsible for cancelling connCtx when the connection ends so
+// this goroutine exits. If either the read pump or this goroutine decides
+// the connection is dead, it signals reconnectC (non-blocking) instead of
+// closing conn directly, so the caller's own select loop drives the
+// reconnect uniformly.
+func armKeepalive(connCtx context.Context, conn *ws.Conn, reconnectC chan struct{}) {
+	conn.SetReadDeadline(time.Now().Add(readDeadline))
+	conn.SetPingHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(readDeadline))
+		return conn.WriteControl(ws.PongMessage, []byte(appData), time.Now().Add(10*time.Second))
+	})
+
+	go func() {
+		ticker := time.NewTicker(pongInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-connCtx.Done():
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(ws.PongMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+					log.Printf("⚠️  Failed to send keepalive pong: %v", err)
+					triggerReconnect(reconnectC)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// triggerReconnect signals reconnectC without blocking if a reconnect is
+// already pending.
+func triggerReconnect(reconnectC chan struct{}) {
+	select {
+	case reconnectC <- struct{}{}:
+	default:
+	}
+}
@@ -1,23 +1,30 @@
 package websocket
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"strconv"
 	"sync"
 	"time"
 
-	"github.com/adshao/go-binance/v2"
+	ws "github.com/gorilla/websocket"
+
+	"github.com/aphis/24hrt-backend/client"
 )
 
 // KlineUpdate represents a candlestick update - MUST MATCH Lightweight Charts format
 type KlineUpdate struct {
-	Symbol string  `json:"symbol"`
-	Time   int64   `json:"time"`   // Unix timestamp in seconds
-	Open   float64 `json:"open"`
-	High   float64 `json:"high"`
-	Low    float64 `json:"low"`
-	Close  float64 `json:"close"`
-	Volume float64 `json:"volume"`
+	Symbol   string  `json:"symbol"`
+	Time     int64   `json:"time"` // Unix timestamp in seconds
+	Open     float64 `json:"open"`
+	High     float64 `json:"high"`
+	Low      float64 `json:"low"`
+	Close    float64 `json:"close"`
+	Volume   float64 `json:"volume"`
+	IsClosed bool    `json:"isClosed"` // true once the candle is final and won't be revised
 }
 
 // KlineStreamer manages WebSocket connection to Binance kline streams
@@ -25,47 +32,185 @@ type KlineStreamer struct {
 	symbol       string
 	interval     string
 	updateChan   chan KlineUpdate
+	haUpdateChan chan KlineUpdate // Heikin-Ashi transform of updateChan, type "kline_ha"
 	errorChan    chan error
 	stopChan     chan struct{}
 	isRunning    bool
-	doneC        chan struct{}
-	stopC        chan struct{}
-	errC         chan error
-	buffer       *DataBuffer // Add buffering capability
-	mu           sync.Mutex  // Add mutex for thread safety
-	compression  bool        // Enable data compression for better performance
+	conn         *ws.Conn              // live connection, kept for ChaosKill
+	connMu       sync.Mutex            // guards conn
+	buffer       *DataBuffer           // Add buffering capability
+	mu           sync.Mutex            // Add mutex for thread safety
+	compression  bool                  // Enable data compression for better performance
+	restClient   *client.TradingClient // Used to backfill the buffer on subscribe; may be nil
+
+	// Heikin-Ashi recurrence state: the last *confirmed* (candle-closed)
+	// haOpen/haClose, carried forward so the still-open candle recomputes
+	// fresh on every tick without ever confirming until it closes.
+	haOpen   float64
+	haClose  float64
+	haSeeded bool
+
+	// lastCloseTime is the CloseTime (ms) of the last closed candle this
+	// streamer has seen, from either backfill or the live stream. It drives
+	// the REST catch-up performed after a reconnect.
+	lastCloseTime int64
 }
 
-// NewKlineStreamer creates a new kline streamer for a given symbol and interval
-func NewKlineStreamer(symbol, interval string) *KlineStreamer {
+// NewKlineStreamer creates a new kline streamer for a given symbol and
+// interval. maxCandles sizes the in-memory rolling window (0 => 1000).
+// restClient is optional; when set, Start seeds the buffer with a REST
+// backfill before the live stream begins so charts don't open empty.
+func NewKlineStreamer(symbol, interval string, maxCandles int, restClient *client.TradingClient) *KlineStreamer {
 	return &KlineStreamer{
-		symbol:      symbol,
-		interval:    interval,
-		updateChan:  make(chan KlineUpdate, 100),
-		errorChan:   make(chan error, 10),
-		stopChan:    make(chan struct{}),
-		doneC:       make(chan struct{}),
-		stopC:       make(chan struct{}),
-		errC:        make(chan error),
-		isRunning:   false,
-		buffer:      NewDataBuffer(symbol, interval), // Initialize buffer
-		compression: true, // Enable compression by default
+		symbol:       symbol,
+		interval:     interval,
+		updateChan:   make(chan KlineUpdate, 100),
+		haUpdateChan: make(chan KlineUpdate, 100),
+		errorChan:    make(chan error, 10),
+		stopChan:     make(chan struct{}),
+		isRunning:    false,
+		buffer:       NewDataBuffer(symbol, interval, maxCandles),
+		compression:  true, // Enable compression by default
+		restClient:   restClient,
 	}
 }
 
-// Start begins listening to kline updates via WebSocket
+// Start begins listening to kline updates via WebSocket, first seeding the
+// buffer with a REST backfill (when a restClient was provided) so newly
+// connected clients get real history instead of an empty chart.
 func (ks *KlineStreamer) Start() error {
 	if ks.isRunning {
 		return fmt.Errorf("streamer already running")
 	}
 
+	if ks.restClient != nil {
+		if err := ks.backfill(); err != nil {
+			log.Printf("⚠️  Kline backfill failed for %s %s: %v", ks.symbol, ks.interval, err)
+		}
+	}
+
 	ks.isRunning = true
 	go ks.startStream()
-	
+
 	log.Printf("🚀 Started Kline WebSocket stream for %s (%s)", ks.symbol, ks.interval)
 	return nil
 }
 
+// Backfill seeds the buffer with up to limit recent closed candles via REST,
+// bound to ctx. It's exported so a caller can explicitly (re)backfill a
+// symbol/interval — e.g. after a gap larger than catchUp's usual
+// post-reconnect window — instead of only ever getting the
+// implicit one Start performs. Duplicate candles are harmless: UpdateKline
+// only appends to history when a candle's Time differs from the last entry.
+func (ks *KlineStreamer) Backfill(ctx context.Context, limit int) error {
+	if ks.restClient == nil {
+		return fmt.Errorf("no REST client configured for %s %s backfill", ks.symbol, ks.interval)
+	}
+
+	klines, err := ks.restClient.GetKlinesWithContext(ctx, ks.symbol, ks.interval, limit)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range klines {
+		open, _ := parseFloat(k.Open)
+		high, _ := parseFloat(k.High)
+		low, _ := parseFloat(k.Low)
+		close, _ := parseFloat(k.Close)
+		volume, _ := parseFloat(k.Volume)
+
+		update := KlineUpdate{
+			Symbol:   ks.symbol,
+			Time:     k.OpenTime / 1000,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    close,
+			Volume:   volume,
+			IsClosed: true,
+		}
+		ks.buffer.UpdateKline(update)
+		ks.computeHeikinAshi(update) // seed haOpen/haClose continuity from history
+
+		ks.mu.Lock()
+		ks.lastCloseTime = k.CloseTime
+		ks.mu.Unlock()
+	}
+
+	log.Printf("📥 Backfilled %d candles for %s %s", len(klines), ks.symbol, ks.interval)
+	return nil
+}
+
+// backfill is Backfill sized to the streamer's full history capacity, called
+// once from Start (via a background context) before the live stream begins.
+func (ks *KlineStreamer) backfill() error {
+	return ks.Backfill(context.Background(), cap(ks.buffer.klineHistory))
+}
+
+// catchUp fetches any candles closed while the stream was down (since
+// lastCloseTime) via REST and replays them as closed updates before the
+// live stream resumes, so subscribed clients see no gap across a
+// reconnect. startStream calls it before every reconnect, never before the
+// first connection (Start's backfill covers that case); it is a no-op while
+// lastCloseTime is still zero or when there is no restClient.
+func (ks *KlineStreamer) catchUp() {
+	ks.mu.Lock()
+	last := ks.lastCloseTime
+	ks.mu.Unlock()
+
+	if last == 0 || ks.restClient == nil {
+		return
+	}
+
+	startTime := strconv.FormatInt(last+1, 10)
+	endTime := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	klines, err := ks.restClient.GetKlinesWithTimeRange(ks.symbol, ks.interval, startTime, endTime, "1000")
+	if err != nil {
+		log.Printf("⚠️  Kline catch-up failed for %s %s: %v", ks.symbol, ks.interval, err)
+		return
+	}
+
+	for _, k := range klines {
+		open, _ := parseFloat(k.Open)
+		high, _ := parseFloat(k.High)
+		low, _ := parseFloat(k.Low)
+		close, _ := parseFloat(k.Close)
+		volume, _ := parseFloat(k.Volume)
+
+		update := KlineUpdate{
+			Symbol:   ks.symbol,
+			Time:     k.OpenTime / 1000,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    close,
+			Volume:   volume,
+			IsClosed: true,
+		}
+
+		ks.buffer.UpdateKline(update)
+		haUpdate := ks.computeHeikinAshi(update)
+
+		select {
+		case ks.updateChan <- update:
+		default:
+		}
+		select {
+		case ks.haUpdateChan <- haUpdate:
+		default:
+		}
+
+		ks.mu.Lock()
+		ks.lastCloseTime = k.CloseTime
+		ks.mu.Unlock()
+	}
+
+	if len(klines) > 0 {
+		log.Printf("📥 Caught up %d missed candle(s) for %s %s after reconnect", len(klines), ks.symbol, ks.interval)
+	}
+}
+
 // Stop gracefully stops the WebSocket stream
 func (ks *KlineStreamer) Stop() {
 	if ks.isRunning {
@@ -85,23 +230,87 @@ func (ks *KlineStreamer) GetErrorChannel() <-chan error {
 	return ks.errorChan
 }
 
-// startStream internal goroutine to handle WebSocket connection
+// GetHAUpdateChannel returns the Heikin-Ashi transform of every update sent
+// on GetUpdateChannel, for callers that want to broadcast a "kline_ha" frame
+// alongside the raw "kline" one.
+func (ks *KlineStreamer) GetHAUpdateChannel() <-chan KlineUpdate {
+	return ks.haUpdateChan
+}
+
+// computeHeikinAshi derives the Heikin-Ashi OHLC for update from the
+// streamer's last confirmed haOpen/haClose (seeded from the first candle
+// seen). It only advances that confirmed state once update.IsClosed, so the
+// still-open candle recomputes fresh from the same prior close on every tick.
+func (ks *KlineStreamer) computeHeikinAshi(update KlineUpdate) KlineUpdate {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	haClose := (update.Open + update.High + update.Low + update.Close) / 4
+
+	var haOpen float64
+	if !ks.haSeeded {
+		haOpen = (update.Open + update.Close) / 2
+	} else {
+		haOpen = (ks.haOpen + ks.haClose) / 2
+	}
+
+	haHigh := math.Max(update.High, math.Max(haOpen, haClose))
+	haLow := math.Min(update.Low, math.Min(haOpen, haClose))
+
+	if update.IsClosed {
+		ks.haOpen = haOpen
+		ks.haClose = haClose
+		ks.haSeeded = true
+	}
+
+	ha := update
+	ha.Open = haOpen
+	ha.High = haHigh
+	ha.Low = haLow
+	ha.Close = haClose
+	return ha
+}
+
+// startStream internal goroutine to handle WebSocket connection. Before
+// every reconnect (after a dropped connection or a failed dial) it performs
+// a REST catch-up for any candles missed while down; failed dials are
+// retried with exponential backoff and jitter (base 1s, capped at
+// maxReconnectDelay), mirroring PriceStreamer's reconnect behavior.
 func (ks *KlineStreamer) startStream() {
+	attempt := 0
+	reconnecting := false
+
 	for {
 		select {
 		case <-ks.stopChan:
 			return
 		default:
-			ks.connectAndListen()
-			// Wait before reconnecting
-			time.Sleep(5 * time.Second)
-			log.Printf("♻️  Attempting to reconnect kline stream for %s...", ks.symbol)
+			if reconnecting {
+				ks.catchUp()
+			}
+			reconnecting = true
+
+			connected := ks.connectAndListen()
+			if connected {
+				attempt = 0
+				continue
+			}
+
+			delay := backoffWithJitter(time.Second, attempt)
+			attempt++
+			log.Printf("♻️  Reconnecting kline stream for %s in %s (attempt %d)...", ks.symbol, delay, attempt)
+			time.Sleep(delay)
 		}
 	}
 }
 
-// connectAndListen establishes WebSocket connection and listens for updates
-func (ks *KlineStreamer) connectAndListen() {
+// connectAndListen dials the kline stream directly (rather than through
+// binance.WsKlineServe, which hides the socket and so can't be fitted with
+// our own read deadline and ping/pong keepalive) and listens for updates. It
+// returns true if a connection was actually established (so the caller can
+// reset its backoff), regardless of how it later ended.
+func (ks *KlineStreamer) connectAndListen() bool {
+	connected := false
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("❌ Panic in kline stream: %v", r)
@@ -109,73 +318,122 @@ func (ks *KlineStreamer) connectAndListen() {
 		}
 	}()
 
-	wsKlineHandler := func(event *binance.WsKlineEvent) {
-		// Parse the kline data
-		kline := event.Kline
-		
-		// Convert strings to float64
-		open, _ := parseFloat(kline.Open)
-		high, _ := parseFloat(kline.High)
-		low, _ := parseFloat(kline.Low)
-		close, _ := parseFloat(kline.Close)
-		volume, _ := parseFloat(kline.Volume)
-		
-		// Convert millisecond timestamp to seconds (Lightweight Charts requirement)
-		timeSeconds := kline.StartTime / 1000
-		
-		update := KlineUpdate{
-			Symbol: event.Symbol,
-			Time:   timeSeconds,
-			Open:   open,
-			High:   high,
-			Low:    low,
-			Close:  close,
-			Volume: volume,
-		}
-		
-		// Update buffer for snapshot capability
-		ks.buffer.UpdateKline(update)
-		
-		select {
-		case ks.updateChan <- update:
-			// Only log on candle close to reduce noise
-			if kline.IsFinal {
-				log.Printf("🕯️  %s %s - Close: %.2f | O: %.2f H: %.2f L: %.2f | Vol: %.2f", 
-					event.Symbol, ks.interval, close, open, high, low, volume)
-			}
-		default:
-			// Channel full, skip this update
-			log.Printf("⚠️  Channel full, skipping update")
-		}
-	}
+	url := fmt.Sprintf("%s?streams=%s", combinedStreamURL, klineStreamName(ks.symbol, ks.interval))
 
-	errHandler := func(err error) {
-		log.Printf("❌ WebSocket error for %s: %v", ks.symbol, err)
-		select {
-		case ks.errorChan <- err:
-		default:
-		}
-	}
-
-	// Start WebSocket kline service
-	doneC, stopC, err := binance.WsKlineServe(ks.symbol, ks.interval, wsKlineHandler, errHandler)
+	conn, _, err := streamDialer.Dial(url, nil)
 	if err != nil {
-		log.Printf("❌ Failed to start kline WebSocket: %v", err)
+		log.Printf("❌ Failed to dial kline stream for %s: %v", ks.symbol, err)
 		ks.errorChan <- err
-		return
+		return connected
 	}
+	connected = true
+
+	ks.connMu.Lock()
+	ks.conn = conn
+	ks.connMu.Unlock()
 
-	ks.doneC = doneC
-	ks.stopC = stopC
+	connCtx, cancel := context.WithCancel(context.Background())
+	reconnectC := make(chan struct{}, 1)
+	armKeepalive(connCtx, conn, reconnectC)
+
+	defer func() {
+		cancel()
+		ks.connMu.Lock()
+		ks.conn = nil
+		ks.connMu.Unlock()
+		conn.Close()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				log.Printf("⚠️  Kline stream read error for %s: %v", ks.symbol, err)
+				triggerReconnect(reconnectC)
+				return
+			}
+			conn.SetReadDeadline(time.Now().Add(readDeadline))
+
+			var envelope combinedStreamEnvelope
+			if err := json.Unmarshal(message, &envelope); err != nil {
+				continue
+			}
+
+			var raw rawKlineStreamEvent
+			if err := json.Unmarshal(envelope.Data, &raw); err != nil {
+				continue
+			}
+
+			open, _ := parseFloat(raw.Kline.Open)
+			high, _ := parseFloat(raw.Kline.High)
+			low, _ := parseFloat(raw.Kline.Low)
+			close, _ := parseFloat(raw.Kline.Close)
+			volume, _ := parseFloat(raw.Kline.Volume)
+
+			update := KlineUpdate{
+				Symbol:   ks.symbol,
+				Time:     raw.Kline.StartTime / 1000,
+				Open:     open,
+				High:     high,
+				Low:      low,
+				Close:    close,
+				Volume:   volume,
+				IsClosed: raw.Kline.IsFinal,
+			}
+
+			// Update buffer for snapshot capability
+			ks.buffer.UpdateKline(update)
+			haUpdate := ks.computeHeikinAshi(update)
+
+			select {
+			case ks.updateChan <- update:
+				// Only log on candle close to reduce noise
+				if update.IsClosed {
+					log.Printf("🕯️  %s %s - Close: %.2f | O: %.2f H: %.2f L: %.2f | Vol: %.2f",
+						ks.symbol, ks.interval, close, open, high, low, volume)
+				}
+			default:
+				// Channel full, skip this update
+				log.Printf("⚠️  Channel full, skipping update")
+			}
+
+			select {
+			case ks.haUpdateChan <- haUpdate:
+			default:
+			}
+
+			if update.IsClosed {
+				ks.mu.Lock()
+				ks.lastCloseTime = raw.Kline.CloseTime
+				ks.mu.Unlock()
+			}
+		}
+	}()
 
-	// Wait for done or stop signal
 	select {
 	case <-ks.stopChan:
-		close(ks.stopC)
-		return
-	case <-ks.doneC:
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return connected
+	case <-reconnectC:
+		log.Printf("♻️  Kline stream liveness check failed for %s, forcing reconnect", ks.symbol)
+		return connected
+	case <-done:
 		log.Printf("⚠️  Kline WebSocket closed for %s", ks.symbol)
-		return
+		return connected
+	}
+}
+
+// ChaosKill force-closes the current upstream kline connection, driving the
+// normal reconnect + REST catch-up path. Intended only for the --chaos test
+// harness.
+func (ks *KlineStreamer) ChaosKill() {
+	ks.connMu.Lock()
+	conn := ks.conn
+	ks.connMu.Unlock()
+	if conn != nil {
+		conn.Close()
 	}
 }
 
@@ -197,10 +455,10 @@ func (ks *KlineStreamer) GetBuffer() *DataBuffer {
 func (ks *KlineStreamer) GetSnapshot() map[string]interface{} {
 	ks.mu.Lock()
 	defer ks.mu.Unlock()
-	
+
 	snapshot := ks.buffer.GetSnapshot()
 	snapshot["isRunning"] = ks.isRunning
 	snapshot["clientCount"] = len(ks.updateChan) // Approximate client count
-	
+
 	return snapshot
 }
@@ -0,0 +1,466 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+
+	"github.com/aphis/24hrt-backend/client"
+)
+
+// klineSubscription is one symbol+interval's slice of a MultiStreamer's
+// shared connection: its own update channel and rolling buffer, same as a
+// standalone KlineStreamer would have.
+type klineSubscription struct {
+	symbol     string
+	interval   string
+	updateChan chan KlineUpdate
+	buffer     *DataBuffer
+}
+
+// rawKlineStreamEvent is the payload of a <symbol>@kline_<interval> event.
+type rawKlineStreamEvent struct {
+	EventType string       `json:"e"`
+	EventTime int64        `json:"E"`
+	Symbol    string       `json:"s"`
+	Kline     rawKlinePart `json:"k"`
+}
+
+type rawKlinePart struct {
+	StartTime int64  `json:"t"`
+	CloseTime int64  `json:"T"`
+	Symbol    string `json:"s"`
+	Interval  string `json:"i"`
+	Open      string `json:"o"`
+	Close     string `json:"c"`
+	High      string `json:"h"`
+	Low       string `json:"l"`
+	Volume    string `json:"v"`
+	IsFinal   bool   `json:"x"`
+}
+
+// streamControlResponse is Binance's reply to a SUBSCRIBE/UNSUBSCRIBE
+// request, correlated back to the pending request via ID.
+type streamControlResponse struct {
+	ID     *int64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
+
+// MultiStreamer fans many symbol/interval kline subscriptions out over a
+// single Binance combined-stream WebSocket connection, instead of one
+// connection per KlineStreamer. Subscriptions can be added or removed at
+// runtime via Subscribe/Unsubscribe, which issue SUBSCRIBE/UNSUBSCRIBE
+// control frames on the live socket (re-sent in bulk on every reconnect via
+// resubscribeAll) instead of tearing the connection down.
+type MultiStreamer struct {
+	ctx context.Context
+
+	subs   map[string]*klineSubscription
+	subsMu sync.Mutex
+
+	conn   *ws.Conn
+	connMu sync.Mutex
+
+	// pending tracks in-flight SUBSCRIBE/UNSUBSCRIBE requests by ID so their
+	// control-frame responses can be correlated and logged.
+	pending   map[int64][]string
+	pendingMu sync.Mutex
+
+	errorChan chan error
+	stopChan  chan struct{}
+	isRunning bool
+
+	requestID      int64
+	reconnectDelay time.Duration
+	maxCandles     int
+	restClient     *client.TradingClient // optional; backfills a subscription's buffer on Subscribe
+}
+
+// NewMultiStreamer creates a new combined kline streamer. restClient is
+// optional; when set, Subscribe seeds the new subscription's buffer with a
+// REST backfill before live updates start arriving, same as KlineStreamer.
+func NewMultiStreamer(ctx context.Context, reconnectDelaySeconds, maxCandles int, restClient *client.TradingClient) *MultiStreamer {
+	if reconnectDelaySeconds <= 0 {
+		reconnectDelaySeconds = 1
+	}
+	if maxCandles <= 0 {
+		maxCandles = 1000
+	}
+
+	return &MultiStreamer{
+		ctx:            ctx,
+		subs:           make(map[string]*klineSubscription),
+		pending:        make(map[int64][]string),
+		errorChan:      make(chan error, 10),
+		stopChan:       make(chan struct{}),
+		reconnectDelay: time.Duration(reconnectDelaySeconds) * time.Second,
+		maxCandles:     maxCandles,
+		restClient:     restClient,
+	}
+}
+
+// Start begins the shared combined-stream connection.
+func (ms *MultiStreamer) Start() error {
+	if ms.isRunning {
+		return fmt.Errorf("streamer already running")
+	}
+
+	ms.isRunning = true
+	go ms.startStream()
+
+	log.Println("🚀 Started multi-symbol kline combined stream")
+	return nil
+}
+
+// Stop gracefully stops the combined stream.
+func (ms *MultiStreamer) Stop() {
+	if ms.isRunning {
+		close(ms.stopChan)
+		ms.isRunning = false
+		log.Println("🛑 Stopped multi-symbol kline combined stream")
+	}
+}
+
+// GetErrorChannel returns the channel for errors.
+func (ms *MultiStreamer) GetErrorChannel() <-chan error {
+	return ms.errorChan
+}
+
+func streamKeyFor(symbol, interval string) string {
+	return strings.ToUpper(symbol) + "_" + interval
+}
+
+func klineStreamName(symbol, interval string) string {
+	return strings.ToLower(symbol) + "@kline_" + interval
+}
+
+// parseKlineStreamName splits "btcusdt@kline_1m" back into symbol/interval.
+func parseKlineStreamName(streamName string) (symbol, interval string, ok bool) {
+	const marker = "@kline_"
+	idx := strings.Index(streamName, marker)
+	if idx < 0 {
+		return "", "", false
+	}
+	return streamName[:idx], streamName[idx+len(marker):], true
+}
+
+// Subscribe adds symbol+interval to the shared connection, returning its
+// update channel. Calling Subscribe again for the same pair is a no-op that
+// just returns the existing channel.
+func (ms *MultiStreamer) Subscribe(symbol, interval string) (<-chan KlineUpdate, error) {
+	symbol = strings.ToUpper(symbol)
+	key := streamKeyFor(symbol, interval)
+
+	ms.subsMu.Lock()
+	if sub, exists := ms.subs[key]; exists {
+		ms.subsMu.Unlock()
+		return sub.updateChan, nil
+	}
+
+	sub := &klineSubscription{
+		symbol:     symbol,
+		interval:   interval,
+		updateChan: make(chan KlineUpdate, 100),
+		buffer:     NewDataBuffer(symbol, interval, ms.maxCandles),
+	}
+	ms.subs[key] = sub
+	ms.subsMu.Unlock()
+
+	if ms.restClient != nil {
+		ms.backfill(sub)
+	}
+
+	if err := ms.sendControl("SUBSCRIBE", []string{klineStreamName(symbol, interval)}); err != nil {
+		return sub.updateChan, err
+	}
+
+	log.Printf("➕ Subscribed to %s %s on multi-kline stream", symbol, interval)
+	return sub.updateChan, nil
+}
+
+// Unsubscribe removes symbol+interval from the shared connection.
+func (ms *MultiStreamer) Unsubscribe(symbol, interval string) error {
+	symbol = strings.ToUpper(symbol)
+	key := streamKeyFor(symbol, interval)
+
+	ms.subsMu.Lock()
+	if _, exists := ms.subs[key]; !exists {
+		ms.subsMu.Unlock()
+		return nil
+	}
+	delete(ms.subs, key)
+	ms.subsMu.Unlock()
+
+	log.Printf("➖ Unsubscribed from %s %s on multi-kline stream", symbol, interval)
+	return ms.sendControl("UNSUBSCRIBE", []string{klineStreamName(symbol, interval)})
+}
+
+// GetBuffer returns the rolling buffer for an active subscription.
+func (ms *MultiStreamer) GetBuffer(symbol, interval string) (*DataBuffer, bool) {
+	ms.subsMu.Lock()
+	defer ms.subsMu.Unlock()
+
+	sub, ok := ms.subs[streamKeyFor(symbol, interval)]
+	if !ok {
+		return nil, false
+	}
+	return sub.buffer, true
+}
+
+// backfill seeds a newly-subscribed symbol+interval's buffer with recent
+// closed candles from the REST API, same as KlineStreamer.backfill.
+func (ms *MultiStreamer) backfill(sub *klineSubscription) {
+	limit := strconv.Itoa(cap(sub.buffer.klineHistory))
+	klines, err := ms.restClient.GetKlines(sub.symbol, sub.interval, limit)
+	if err != nil {
+		log.Printf("⚠️  Multi-kline backfill failed for %s %s: %v", sub.symbol, sub.interval, err)
+		return
+	}
+
+	for _, k := range klines {
+		open, _ := parseFloat(k.Open)
+		high, _ := parseFloat(k.High)
+		low, _ := parseFloat(k.Low)
+		close, _ := parseFloat(k.Close)
+		volume, _ := parseFloat(k.Volume)
+
+		sub.buffer.UpdateKline(KlineUpdate{
+			Symbol:   sub.symbol,
+			Time:     k.OpenTime / 1000,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    close,
+			Volume:   volume,
+			IsClosed: true,
+		})
+	}
+
+	log.Printf("📥 Backfilled %d candles for %s %s (multi-stream)", len(klines), sub.symbol, sub.interval)
+}
+
+// sendControl writes a SUBSCRIBE/UNSUBSCRIBE JSON-RPC frame on the live
+// connection, recording the request ID so its response can be correlated.
+// If no connection is open yet, the subscription set change was already
+// recorded in ms.subs, so the next connect's resubscribeAll covers it.
+func (ms *MultiStreamer) sendControl(method string, params []string) error {
+	ms.connMu.Lock()
+	conn := ms.conn
+	ms.connMu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	id := atomic.AddInt64(&ms.requestID, 1)
+	req := streamControlRequest{Method: method, Params: params, ID: id}
+
+	ms.pendingMu.Lock()
+	ms.pending[id] = params
+	ms.pendingMu.Unlock()
+
+	ms.connMu.Lock()
+	defer ms.connMu.Unlock()
+	return conn.WriteJSON(req)
+}
+
+// resubscribeAll re-sends a single bulk SUBSCRIBE frame covering every
+// currently active subscription, called right after a (re)connect so no
+// subscription is lost across a dropped socket.
+func (ms *MultiStreamer) resubscribeAll() error {
+	ms.subsMu.Lock()
+	names := make([]string, 0, len(ms.subs))
+	for _, sub := range ms.subs {
+		names = append(names, klineStreamName(sub.symbol, sub.interval))
+	}
+	ms.subsMu.Unlock()
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return ms.sendControl("SUBSCRIBE", names)
+}
+
+// startStream reconnects with exponential backoff and jitter, same as
+// PriceStreamer/KlineStreamer.
+func (ms *MultiStreamer) startStream() {
+	attempt := 0
+
+	for {
+		select {
+		case <-ms.stopChan:
+			return
+		case <-ms.ctx.Done():
+			return
+		default:
+			connected := ms.connectAndListen()
+			if connected {
+				attempt = 0
+				continue
+			}
+
+			delay := backoffWithJitter(ms.reconnectDelay, attempt)
+			attempt++
+			log.Printf("♻️  Reconnecting multi-kline stream in %s (attempt %d)...", delay, attempt)
+
+			select {
+			case <-ms.stopChan:
+				return
+			case <-ms.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}
+}
+
+// connectAndListen dials the base combined-stream endpoint (with no initial
+// streams, unlike PriceStreamer) and drives every subscription entirely via
+// SUBSCRIBE/UNSUBSCRIBE control frames, resubscribing in bulk once
+// connected. It returns true if a connection was actually established.
+func (ms *MultiStreamer) connectAndListen() bool {
+	connected := false
+
+	conn, _, err := streamDialer.Dial(combinedStreamURL, nil)
+	if err != nil {
+		log.Printf("❌ Failed to dial multi-kline stream: %v", err)
+		ms.errorChan <- err
+		return connected
+	}
+
+	ms.connMu.Lock()
+	ms.conn = conn
+	ms.connMu.Unlock()
+	connected = true
+
+	defer func() {
+		ms.connMu.Lock()
+		ms.conn = nil
+		ms.connMu.Unlock()
+		conn.Close()
+	}()
+
+	if err := ms.resubscribeAll(); err != nil {
+		log.Printf("⚠️  Failed to resubscribe streams after (re)connect: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				log.Printf("⚠️  Multi-kline stream read error: %v", err)
+				return
+			}
+			ms.handleMessage(message)
+		}
+	}()
+
+	select {
+	case <-ms.stopChan:
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return connected
+	case <-ms.ctx.Done():
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return connected
+	case <-done:
+		log.Println("⚠️  Multi-kline stream connection closed")
+		return connected
+	}
+}
+
+// handleMessage dispatches an incoming frame to either the kline-event
+// router or the control-response correlator, based on its shape.
+func (ms *MultiStreamer) handleMessage(message []byte) {
+	var envelope combinedStreamEnvelope
+	if err := json.Unmarshal(message, &envelope); err == nil && envelope.Stream != "" {
+		ms.routeEvent(envelope.Stream, envelope.Data)
+		return
+	}
+
+	var resp streamControlResponse
+	if err := json.Unmarshal(message, &resp); err == nil && resp.ID != nil {
+		ms.pendingMu.Lock()
+		streams, ok := ms.pending[*resp.ID]
+		delete(ms.pending, *resp.ID)
+		ms.pendingMu.Unlock()
+
+		if resp.Error != nil {
+			log.Printf("❌ Stream control request %d failed: %s", *resp.ID, resp.Error.Msg)
+		} else if ok {
+			log.Printf("✅ Stream control request %d acknowledged (%v)", *resp.ID, streams)
+		}
+	}
+}
+
+// routeEvent parses a raw kline event and forwards it to its subscription's
+// update channel and buffer, if still subscribed.
+func (ms *MultiStreamer) routeEvent(streamName string, data json.RawMessage) {
+	symbol, interval, ok := parseKlineStreamName(streamName)
+	if !ok {
+		return
+	}
+
+	var raw rawKlineStreamEvent
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+
+	ms.subsMu.Lock()
+	sub, exists := ms.subs[streamKeyFor(symbol, interval)]
+	ms.subsMu.Unlock()
+	if !exists {
+		return
+	}
+
+	open, _ := parseFloat(raw.Kline.Open)
+	high, _ := parseFloat(raw.Kline.High)
+	low, _ := parseFloat(raw.Kline.Low)
+	close, _ := parseFloat(raw.Kline.Close)
+	volume, _ := parseFloat(raw.Kline.Volume)
+
+	update := KlineUpdate{
+		Symbol:   strings.ToUpper(symbol),
+		Time:     raw.Kline.StartTime / 1000,
+		Open:     open,
+		High:     high,
+		Low:      low,
+		Close:    close,
+		Volume:   volume,
+		IsClosed: raw.Kline.IsFinal,
+	}
+
+	sub.buffer.UpdateKline(update)
+
+	select {
+	case sub.updateChan <- update:
+	default:
+		log.Printf("⚠️  Multi-kline channel full for %s %s, skipping update", symbol, interval)
+	}
+}
+
+// ChaosKill force-closes the current upstream connection, driving the
+// normal reconnect + resubscribe path. Intended only for the --chaos test
+// harness.
+func (ms *MultiStreamer) ChaosKill() {
+	ms.connMu.Lock()
+	conn := ms.conn
+	ms.connMu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
@@ -2,11 +2,39 @@ package websocket
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
+	ws "github.com/gorilla/websocket"
+)
+
+// combinedStreamURL is Binance's mainnet combined-stream endpoint, which
+// multiplexes any number of raw streams (e.g. btcusdt@trade) over a single
+// connection. It's the default for both PriceStreamer and BookStreamer, and
+// can be hot-swapped per-instance via PriceStreamer.UpdateURL.
+const combinedStreamURL = "wss://stream.binance.com:9443/stream"
+
+// TestnetStreamURL is Binance's testnet combined-stream endpoint.
+const TestnetStreamURL = "wss://stream.testnet.binance.vision/stream"
+
+// maxReconnectDelay caps the exponential backoff between reconnect attempts.
+const maxReconnectDelay = 60 * time.Second
+
+// ConnectionState describes the lifecycle of a streamer's upstream socket.
+type ConnectionState string
+
+const (
+	StateConnecting   ConnectionState = "connecting"
+	StateConnected    ConnectionState = "connected"
+	StateReconnecting ConnectionState = "reconnecting"
+	StateDisconnected ConnectionState = "disconnected"
 )
 
 type PriceUpdate struct {
@@ -15,26 +43,87 @@ type PriceUpdate struct {
 	Timestamp int64
 }
 
+// streamControlRequest mirrors Binance's JSON-RPC SUBSCRIBE/UNSUBSCRIBE frame,
+// sent over the already-open combined stream connection.
+type streamControlRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int64    `json:"id"`
+}
+
+// combinedStreamEnvelope wraps every event delivered on the combined stream.
+type combinedStreamEnvelope struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// rawTradeEvent is the payload of a <symbol>@trade stream event.
+type rawTradeEvent struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	Price     string `json:"p"`
+}
+
+// PriceStreamer maintains a single combined WebSocket connection covering
+// every symbol it is asked to watch. Symbols can be added or removed at
+// runtime via AddSymbol/RemoveSymbol, which reissue Binance SUBSCRIBE /
+// UNSUBSCRIBE frames over the live socket instead of reconnecting.
 type PriceStreamer struct {
-	symbol       string
-	updateChan   chan PriceUpdate
-	errorChan    chan error
-	stopChan     chan struct{}
-	isRunning    bool
+	ctx context.Context
+
+	symbols   map[string]bool
+	symbolsMu sync.Mutex
+
+	conn   *ws.Conn
+	connMu sync.Mutex
+
+	url   string
+	urlMu sync.Mutex
+
+	updateChan chan PriceUpdate
+	errorChan  chan error
+	stateChan  chan ConnectionState
+	stopChan   chan struct{}
+	isRunning  bool
+
+	requestID      int64
+	reconnectDelay time.Duration
 }
 
-// NewPriceStreamer creates a new price streamer for a given symbol
-func NewPriceStreamer(symbol string) *PriceStreamer {
+// NewPriceStreamer creates a new combined-stream price streamer seeded with
+// the given initial symbols (lowercase or uppercase, either is accepted).
+// ctx scopes the streamer's lifetime: cancelling it stops the stream the same
+// way Stop does. reconnectDelaySeconds is the base exponential-backoff delay
+// (typically config.Config.WSReconnectDelay); it is doubled on every
+// consecutive failure up to maxReconnectDelay and jittered by ±20%.
+func NewPriceStreamer(ctx context.Context, symbols []string, reconnectDelaySeconds int) *PriceStreamer {
+	seed := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		if s == "" {
+			continue
+		}
+		seed[strings.ToUpper(s)] = true
+	}
+
+	if reconnectDelaySeconds <= 0 {
+		reconnectDelaySeconds = 1
+	}
+
 	return &PriceStreamer{
-		symbol:     symbol,
-		updateChan: make(chan PriceUpdate, 100),
-		errorChan:  make(chan error, 10),
-		stopChan:   make(chan struct{}),
-		isRunning:  false,
+		ctx:            ctx,
+		symbols:        seed,
+		url:            combinedStreamURL,
+		updateChan:     make(chan PriceUpdate, 100),
+		errorChan:      make(chan error, 10),
+		stateChan:      make(chan ConnectionState, 10),
+		stopChan:       make(chan struct{}),
+		isRunning:      false,
+		reconnectDelay: time.Duration(reconnectDelaySeconds) * time.Second,
 	}
 }
 
-// Start begins listening to price updates via WebSocket
+// Start begins listening to price updates via the combined WebSocket stream.
 func (ps *PriceStreamer) Start() error {
 	if ps.isRunning {
 		return fmt.Errorf("streamer already running")
@@ -42,103 +131,336 @@ func (ps *PriceStreamer) Start() error {
 
 	ps.isRunning = true
 	go ps.startStream()
-	
-	log.Printf("🚀 Started WebSocket stream for %s", ps.symbol)
+
+	log.Printf("🚀 Started combined WebSocket stream for %d symbol(s)", len(ps.Symbols()))
 	return nil
 }
 
-// Stop gracefully stops the WebSocket stream
+// Stop gracefully stops the WebSocket stream: it signals connectAndListen to
+// send a close frame on the live socket (if any), then drains whatever
+// updates were still queued on updateChan, discarding them.
 func (ps *PriceStreamer) Stop() {
 	if ps.isRunning {
 		close(ps.stopChan)
 		ps.isRunning = false
-		log.Println("🛑 Stopped WebSocket stream")
+
+		for {
+			select {
+			case <-ps.updateChan:
+			default:
+				log.Println("🛑 Stopped WebSocket stream")
+				return
+			}
+		}
 	}
 }
 
-// GetUpdateChannel returns the channel for price updates
+// GetUpdateChannel returns the channel for price updates.
 func (ps *PriceStreamer) GetUpdateChannel() <-chan PriceUpdate {
 	return ps.updateChan
 }
 
-// GetErrorChannel returns the channel for errors
+// GetErrorChannel returns the channel for errors.
 func (ps *PriceStreamer) GetErrorChannel() <-chan error {
 	return ps.errorChan
 }
 
-// startStream internal goroutine to handle WebSocket connection
+// GetStateChannel returns the channel of connection state transitions
+// (Connecting, Connected, Reconnecting, Disconnected) so the frontend can
+// render live stream status.
+func (ps *PriceStreamer) GetStateChannel() <-chan ConnectionState {
+	return ps.stateChan
+}
+
+// UpdateURL atomically swaps the combined-stream endpoint (e.g. between
+// testnet and mainnet) and forces a reconnect against the new URL without
+// losing the current subscription set.
+func (ps *PriceStreamer) UpdateURL(newURL string) {
+	ps.urlMu.Lock()
+	ps.url = newURL
+	ps.urlMu.Unlock()
+
+	log.Printf("🔀 Stream URL updated to %s, forcing reconnect", newURL)
+
+	ps.connMu.Lock()
+	conn := ps.conn
+	ps.connMu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// ChaosKill force-closes the current upstream connection (if any), driving
+// the normal reconnect + re-subscribe path. Intended only for the --chaos
+// test harness.
+func (ps *PriceStreamer) ChaosKill() {
+	ps.connMu.Lock()
+	conn := ps.conn
+	ps.connMu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (ps *PriceStreamer) currentURL() string {
+	ps.urlMu.Lock()
+	defer ps.urlMu.Unlock()
+	return ps.url
+}
+
+func (ps *PriceStreamer) emitState(state ConnectionState) {
+	select {
+	case ps.stateChan <- state:
+	default:
+	}
+}
+
+// Symbols returns the set of symbols currently being watched.
+func (ps *PriceStreamer) Symbols() []string {
+	ps.symbolsMu.Lock()
+	defer ps.symbolsMu.Unlock()
+
+	out := make([]string, 0, len(ps.symbols))
+	for s := range ps.symbols {
+		out = append(out, s)
+	}
+	return out
+}
+
+// AddSymbol starts watching a new symbol, reissuing a SUBSCRIBE frame on the
+// live socket when one is already connected instead of tearing it down.
+func (ps *PriceStreamer) AddSymbol(symbol string) error {
+	symbol = strings.ToUpper(symbol)
+
+	ps.symbolsMu.Lock()
+	if ps.symbols[symbol] {
+		ps.symbolsMu.Unlock()
+		return nil
+	}
+	ps.symbols[symbol] = true
+	ps.symbolsMu.Unlock()
+
+	log.Printf("➕ Watching %s", symbol)
+	return ps.sendControl("SUBSCRIBE", []string{streamName(symbol)})
+}
+
+// RemoveSymbol stops watching a symbol, reissuing an UNSUBSCRIBE frame on the
+// live socket.
+func (ps *PriceStreamer) RemoveSymbol(symbol string) error {
+	symbol = strings.ToUpper(symbol)
+
+	ps.symbolsMu.Lock()
+	if !ps.symbols[symbol] {
+		ps.symbolsMu.Unlock()
+		return nil
+	}
+	delete(ps.symbols, symbol)
+	ps.symbolsMu.Unlock()
+
+	log.Printf("➖ Unwatching %s", symbol)
+	return ps.sendControl("UNSUBSCRIBE", []string{streamName(symbol)})
+}
+
+func streamName(symbol string) string {
+	return strings.ToLower(symbol) + "@trade"
+}
+
+// sendControl writes a SUBSCRIBE/UNSUBSCRIBE JSON-RPC frame on the live
+// connection. If no connection is currently open, the symbol set change is
+// picked up automatically on the next (re)connect, so this is a no-op.
+func (ps *PriceStreamer) sendControl(method string, params []string) error {
+	ps.connMu.Lock()
+	conn := ps.conn
+	ps.connMu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	req := streamControlRequest{
+		Method: method,
+		Params: params,
+		ID:     atomic.AddInt64(&ps.requestID, 1),
+	}
+
+	ps.connMu.Lock()
+	defer ps.connMu.Unlock()
+	return conn.WriteJSON(req)
+}
+
+// startStream internal goroutine to handle WebSocket connection. On failure
+// it reconnects with exponential backoff (base reconnectDelay, capped at
+// maxReconnectDelay, jittered ±20%), resetting the backoff after every
+// connection that was actually established.
 func (ps *PriceStreamer) startStream() {
+	attempt := 0
+
 	for {
 		select {
 		case <-ps.stopChan:
+			ps.emitState(StateDisconnected)
+			return
+		case <-ps.ctx.Done():
+			ps.emitState(StateDisconnected)
 			return
 		default:
-			ps.connectAndListen()
-			// Wait before reconnecting
-			time.Sleep(5 * time.Second)
-			log.Println("♻️  Attempting to reconnect...")
+			if attempt == 0 {
+				ps.emitState(StateConnecting)
+			} else {
+				ps.emitState(StateReconnecting)
+			}
+
+			connected := ps.connectAndListen()
+			if connected {
+				attempt = 0
+				continue
+			}
+
+			delay := backoffWithJitter(ps.reconnectDelay, attempt)
+			attempt++
+			log.Printf("♻️  Reconnecting in %s (attempt %d)...", delay, attempt)
+
+			select {
+			case <-ps.stopChan:
+				ps.emitState(StateDisconnected)
+				return
+			case <-ps.ctx.Done():
+				ps.emitState(StateDisconnected)
+				return
+			case <-time.After(delay):
+			}
 		}
 	}
 }
 
-// connectAndListen establishes WebSocket connection and listens for updates
-func (ps *PriceStreamer) connectAndListen() {
-	wsTradeHandler := func(event *binance.WsTradeEvent) {
-		update := PriceUpdate{
-			Symbol:    event.Symbol,
-			Price:     event.Price,
-			Timestamp: event.Time,
-		}
-		
-		select {
-		case ps.updateChan <- update:
-			log.Printf("💰 %s: %s", update.Symbol, update.Price)
-		default:
-			// Channel full, skip this update
+// backoffWithJitter doubles base for every attempt (capped at
+// maxReconnectDelay) and applies up to ±20% jitter.
+func backoffWithJitter(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxReconnectDelay {
+			delay = maxReconnectDelay
+			break
 		}
 	}
 
-	errHandler := func(err error) {
-		log.Printf("⚠️  WebSocket error: %v", err)
-		select {
-		case ps.errorChan <- err:
-		default:
-		}
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(delay))
+	delay += jitter
+	if delay < 0 {
+		delay = base
+	}
+	return delay
+}
+
+// connectAndListen dials the combined stream for every currently-watched
+// symbol and forwards trade events until the socket closes or Stop is
+// called. It returns true if a connection was actually established (so the
+// caller can reset its backoff), regardless of how it later ended.
+func (ps *PriceStreamer) connectAndListen() bool {
+	symbols := ps.Symbols()
+	if len(symbols) == 0 {
+		// Nothing to watch yet; avoid a tight reconnect loop.
+		time.Sleep(time.Second)
+		return false
 	}
 
-	// Subscribe to trade stream (most real-time data)
-	doneC, stopC, err := binance.WsTradeServe(ps.symbol, wsTradeHandler, errHandler)
+	streams := make([]string, len(symbols))
+	for i, s := range symbols {
+		streams[i] = streamName(s)
+	}
+	url := fmt.Sprintf("%s?streams=%s", ps.currentURL(), strings.Join(streams, "/"))
+
+	conn, _, err := streamDialer.Dial(url, nil)
 	if err != nil {
-		log.Printf("❌ Failed to start WebSocket: %v", err)
+		log.Printf("❌ Failed to dial combined stream: %v", err)
 		ps.errorChan <- err
-		return
+		return false
 	}
 
-	// Wait for done signal or stop command
+	ps.connMu.Lock()
+	ps.conn = conn
+	ps.connMu.Unlock()
+	ps.emitState(StateConnected)
+
+	connCtx, cancel := context.WithCancel(ps.ctx)
+	reconnectC := make(chan struct{}, 1)
+	armKeepalive(connCtx, conn, reconnectC)
+
+	defer func() {
+		cancel()
+		ps.connMu.Lock()
+		ps.conn = nil
+		ps.connMu.Unlock()
+		conn.Close()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				log.Printf("⚠️  Combined stream read error: %v", err)
+				triggerReconnect(reconnectC)
+				return
+			}
+			conn.SetReadDeadline(time.Now().Add(readDeadline))
+
+			var envelope combinedStreamEnvelope
+			if err := json.Unmarshal(message, &envelope); err != nil {
+				continue
+			}
+
+			var trade rawTradeEvent
+			if err := json.Unmarshal(envelope.Data, &trade); err != nil {
+				continue
+			}
+			if trade.EventType != "trade" {
+				continue
+			}
+
+			update := PriceUpdate{
+				Symbol:    trade.Symbol,
+				Price:     trade.Price,
+				Timestamp: trade.EventTime,
+			}
+
+			select {
+			case ps.updateChan <- update:
+			default:
+				// Channel full, skip this update
+			}
+		}
+	}()
+
 	select {
 	case <-ps.stopChan:
-		stopC <- struct{}{}
-		return
-	case <-doneC:
-		log.Println("⚠️  WebSocket connection closed")
-		return
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return true
+	case <-ps.ctx.Done():
+		conn.WriteMessage(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""))
+		return true
+	case <-reconnectC:
+		log.Println("♻️  Combined stream liveness check failed, forcing reconnect")
+		return true
+	case <-done:
+		log.Println("⚠️  Combined stream connection closed")
+		return true
 	}
 }
 
-// StartKlineStream alternative method for Kline/Candlestick data
-func (ps *PriceStreamer) StartKlineStream(interval string) error {
-	if ps.isRunning {
-		return fmt.Errorf("streamer already running")
-	}
-
-	ps.isRunning = true
-	go ps.startKlineStream(interval)
-	
-	log.Printf("🚀 Started Kline stream for %s (%s interval)", ps.symbol, interval)
+// StartKlineStream alternative method for single-symbol Kline/Candlestick data.
+// Unlike the combined trade stream above, this keeps using the SDK's
+// WsKlineServe helper since it is only ever used for one symbol/interval; it
+// does not reconnect once that connection closes.
+func (ps *PriceStreamer) StartKlineStream(symbol, interval string) error {
+	go ps.startKlineStream(symbol, interval)
+	log.Printf("🚀 Started Kline stream for %s (%s interval)", symbol, interval)
 	return nil
 }
 
-func (ps *PriceStreamer) startKlineStream(interval string) {
+func (ps *PriceStreamer) startKlineStream(symbol, interval string) {
 	wsKlineHandler := func(event *binance.WsKlineEvent) {
 		kline := event.Kline
 		update := PriceUpdate{
@@ -146,7 +468,7 @@ func (ps *PriceStreamer) startKlineStream(interval string) {
 			Price:     kline.Close,
 			Timestamp: event.Time,
 		}
-		
+
 		select {
 		case ps.updateChan <- update:
 			log.Printf("📊 %s [%s]: %s", update.Symbol, interval, update.Price)
@@ -162,7 +484,7 @@ func (ps *PriceStreamer) startKlineStream(interval string) {
 		}
 	}
 
-	doneC, stopC, err := binance.WsKlineServe(ps.symbol, interval, wsKlineHandler, errHandler)
+	doneC, stopC, err := binance.WsKlineServe(symbol, interval, wsKlineHandler, errHandler)
 	if err != nil {
 		log.Printf("❌ Failed to start Kline WebSocket: %v", err)
 		ps.errorChan <- err
@@ -173,13 +495,16 @@ func (ps *PriceStreamer) startKlineStream(interval string) {
 	case <-ps.stopChan:
 		stopC <- struct{}{}
 		return
+	case <-ps.ctx.Done():
+		stopC <- struct{}{}
+		return
 	case <-doneC:
 		log.Println("⚠️  Kline WebSocket connection closed")
 		return
 	}
 }
 
-// GetCurrentPrice fetches current price using REST API (for initial state)
+// GetCurrentPrice fetches current price using REST API (for initial state).
 func GetCurrentPrice(symbol string) (string, error) {
 	client := binance.NewClient("", "")
 	prices, err := client.NewListPricesService().Symbol(symbol).Do(context.Background())
@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBackoffWithJitterBounds asserts the reconnect backoff (chunk1-4) is
+// never negative, never exceeds maxReconnectDelay plus its 20% jitter, and
+// from attempt 4 on never drops below half the previous delay, since a bug
+// here would either hammer Binance on every disconnect or stall reconnects
+// for far longer than the stated 60s cap.
+func TestBackoffWithJitterBounds(t *testing.T) {
+	base := time.Second
+	var prev time.Duration
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := backoffWithJitter(base, attempt)
+		if delay < 0 {
+			t.Fatalf("attempt %d: backoffWithJitter returned negative delay %s", attempt, delay)
+		}
+		maxAllowed := time.Duration(float64(maxReconnectDelay) * 1.2)
+		if delay > maxAllowed {
+			t.Fatalf("attempt %d: delay %s exceeds maxReconnectDelay+jitter (%s)", attempt, delay, maxAllowed)
+		}
+		if attempt > 3 && delay < prev/2 {
+			t.Fatalf("attempt %d: delay %s didn't grow with attempt count (prev %s)", attempt, delay, prev)
+		}
+		prev = delay
+	}
+}
+
+// TestDataBufferCatchUpMergeNoDuplicates asserts the core guarantee of
+// chunk1-4's reconnect catch-up: replaying candles fetched from the REST
+// backfill after a reconnect (some of which overlap the last candle seen
+// before the drop) must not duplicate that boundary candle or lose any of
+// the missed ones — the dedup key is candle Time, same as live UpdateKline
+// calls use.
+func TestDataBufferCatchUpMergeNoDuplicates(t *testing.T) {
+	buf := NewDataBuffer("BTCUSDT", "1m", 0)
+
+	// Pre-disconnect: candles at t=0,1,2 arrive live.
+	for _, ts := range []int64{0, 1, 2} {
+		buf.UpdateKline(KlineUpdate{Symbol: "BTCUSDT", Time: ts, Close: float64(ts), IsClosed: true})
+	}
+
+	// Reconnect catch-up re-delivers the last-seen candle (t=2, possibly
+	// revised) plus the candles missed while the socket was down (t=3,4).
+	for _, ts := range []int64{2, 3, 4} {
+		buf.UpdateKline(KlineUpdate{Symbol: "BTCUSDT", Time: ts, Close: float64(ts) + 0.5, IsClosed: true})
+	}
+
+	history := buf.GetHistory(0)
+	wantTimes := []int64{0, 1, 2, 3, 4}
+	if len(history) != len(wantTimes) {
+		t.Fatalf("got %d candles after catch-up, want %d: %+v", len(history), len(wantTimes), history)
+	}
+	for i, want := range wantTimes {
+		if history[i].Time != want {
+			t.Fatalf("candle %d: got Time=%d, want %d", i, history[i].Time, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+)
+
+// StreamStub is the streaming counterpart to the exchange package's
+// stubExchange: it satisfies ExchangeStream so the BroadcastHub's stream
+// registry has somewhere to dispatch FTX-style and KuCoin-style venues, but
+// every call fails fast until a real handshake and feed are wired in behind
+// name.
+type StreamStub struct {
+	StandardStream
+	name string
+}
+
+// NewKuCoinStream returns a placeholder KuCoin-style stream. A real
+// implementation's Connect would first POST to KuCoin's REST
+// /api/v1/bullet-public to mint a short-lived "bullet" token and WS
+// endpoint, then dial that endpoint — KuCoin doesn't expose a fixed WS URL
+// the way Binance does.
+func NewKuCoinStream() *StreamStub {
+	return &StreamStub{name: "kucoin"}
+}
+
+// NewFTXStream returns a placeholder FTX-style stream. A real
+// implementation's Connect would dial FTX's fixed WS endpoint and then send
+// a signed {"op":"login",...} frame before any subscribe frame is accepted.
+func NewFTXStream() *StreamStub {
+	return &StreamStub{name: "ftx"}
+}
+
+func (s *StreamStub) Name() string { return s.name }
+
+func (s *StreamStub) Connect(ctx context.Context) error {
+	return s.notImplemented("Connect")
+}
+
+func (s *StreamStub) Subscribe(symbol, interval string) error {
+	return s.notImplemented("Subscribe")
+}
+
+func (s *StreamStub) Unsubscribe(symbol, interval string) error {
+	return s.notImplemented("Unsubscribe")
+}
+
+func (s *StreamStub) notImplemented(op string) error {
+	return fmt.Errorf("%s: %s not implemented, no live API client configured for this venue", s.name, op)
+}
@@ -0,0 +1,208 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// Ticker24h holds one symbol's rolling 24hr statistics, refreshed on every
+// !ticker@arr event.
+type Ticker24h struct {
+	Symbol             string `json:"symbol"`
+	Price              string `json:"price"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	High               string `json:"high24h"`
+	Low                string `json:"low24h"`
+	Volume             string `json:"volume24h"`
+	QuoteVolume        string `json:"quoteVolume24h"`
+}
+
+// TickerStreamer maintains a single persistent subscription to Binance's
+// all-market ticker stream (!ticker@arr) and keeps an in-memory snapshot of
+// every symbol's latest 24hr statistics, so REST reads are O(1) and
+// real-time pushes are just a cache read away.
+type TickerStreamer struct {
+	ctx context.Context
+
+	mu      sync.RWMutex
+	tickers map[string]Ticker24h
+
+	updateChan chan Ticker24h
+	errorChan  chan error
+	stopChan   chan struct{}
+	doneC      chan struct{}
+	stopC      chan struct{}
+	isRunning  bool
+}
+
+// NewTickerStreamer creates a new all-market ticker streamer. ctx scopes its
+// lifetime the same way PriceStreamer's and MultiStreamer's ctx does:
+// cancelling it ends the stream.
+func NewTickerStreamer(ctx context.Context) *TickerStreamer {
+	return &TickerStreamer{
+		ctx:        ctx,
+		tickers:    make(map[string]Ticker24h),
+		updateChan: make(chan Ticker24h, 200),
+		errorChan:  make(chan error, 10),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start begins listening to the all-market ticker stream.
+func (ts *TickerStreamer) Start() error {
+	if ts.isRunning {
+		return fmt.Errorf("streamer already running")
+	}
+
+	ts.isRunning = true
+	go ts.startStream()
+
+	log.Println("🚀 Started all-market ticker WebSocket stream")
+	return nil
+}
+
+// Stop gracefully stops the stream.
+func (ts *TickerStreamer) Stop() {
+	if ts.isRunning {
+		close(ts.stopChan)
+		ts.isRunning = false
+		log.Println("🛑 Stopped ticker WebSocket stream")
+	}
+}
+
+// GetUpdateChannel returns the channel of individual ticker updates, for
+// callers that want to forward them (e.g. to broadcastHub) as they arrive.
+func (ts *TickerStreamer) GetUpdateChannel() <-chan Ticker24h {
+	return ts.updateChan
+}
+
+// GetErrorChannel returns the channel for errors.
+func (ts *TickerStreamer) GetErrorChannel() <-chan error {
+	return ts.errorChan
+}
+
+// Get returns the last known 24hr ticker for symbol, if any has arrived yet.
+func (ts *TickerStreamer) Get(symbol string) (Ticker24h, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	t, ok := ts.tickers[symbol]
+	return t, ok
+}
+
+// GetAll returns a snapshot of every symbol's latest 24hr ticker.
+func (ts *TickerStreamer) GetAll() map[string]Ticker24h {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	out := make(map[string]Ticker24h, len(ts.tickers))
+	for symbol, t := range ts.tickers {
+		out[symbol] = t
+	}
+	return out
+}
+
+// startStream reconnects with exponential backoff and jitter, same as
+// PriceStreamer/KlineStreamer.
+func (ts *TickerStreamer) startStream() {
+	attempt := 0
+
+	for {
+		select {
+		case <-ts.stopChan:
+			return
+		case <-ts.ctx.Done():
+			return
+		default:
+			connected := ts.connectAndListen()
+			if connected {
+				attempt = 0
+				continue
+			}
+
+			delay := backoffWithJitter(time.Second, attempt)
+			attempt++
+			log.Printf("♻️  Reconnecting ticker stream in %s (attempt %d)...", delay, attempt)
+			time.Sleep(delay)
+		}
+	}
+}
+
+// connectAndListen subscribes to the all-market ticker stream and updates
+// the cache until the socket closes or Stop is called. It returns true if a
+// connection was actually established, so the caller can reset its backoff.
+func (ts *TickerStreamer) connectAndListen() bool {
+	connected := false
+
+	handler := func(event binance.WsAllMarketsStatEvent) {
+		for _, e := range event {
+			ticker := Ticker24h{
+				Symbol:             e.Symbol,
+				Price:              e.LastPrice,
+				PriceChange:        e.PriceChange,
+				PriceChangePercent: e.PriceChangePercent,
+				High:               e.HighPrice,
+				Low:                e.LowPrice,
+				Volume:             e.BaseVolume,
+				QuoteVolume:        e.QuoteVolume,
+			}
+
+			ts.mu.Lock()
+			ts.tickers[ticker.Symbol] = ticker
+			ts.mu.Unlock()
+
+			select {
+			case ts.updateChan <- ticker:
+			default:
+				// Channel full, skip this update; GetAll/Get still have the latest.
+			}
+		}
+	}
+
+	errHandler := func(err error) {
+		log.Printf("❌ Ticker WebSocket error: %v", err)
+		select {
+		case ts.errorChan <- err:
+		default:
+		}
+	}
+
+	doneC, stopC, err := binance.WsAllMarketsStatServe(handler, errHandler)
+	if err != nil {
+		log.Printf("❌ Failed to start ticker WebSocket: %v", err)
+		ts.errorChan <- err
+		return connected
+	}
+
+	connected = true
+	ts.doneC = doneC
+	ts.stopC = stopC
+
+	select {
+	case <-ts.stopChan:
+		close(ts.stopC)
+		return connected
+	case <-ts.ctx.Done():
+		close(ts.stopC)
+		return connected
+	case <-ts.doneC:
+		log.Println("⚠️  Ticker WebSocket closed")
+		return connected
+	}
+}
+
+// ChaosKill force-closes the current upstream connection, driving the
+// normal reconnect path. Intended only for the --chaos test harness.
+func (ts *TickerStreamer) ChaosKill() {
+	if ts.stopC != nil {
+		select {
+		case ts.stopC <- struct{}{}:
+		default:
+		}
+	}
+}